@@ -1,86 +1,150 @@
 package main
 
 import (
-	_ "embed"
+	"errors"
 	"flag"
-	"io"
+	"fmt"
+	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
 	"strings"
 	"text/template"
 
-	"github.com/ichiban/soa/internal/gen"
+	"github.com/ichiban/soa/internal/diff"
+	"github.com/ichiban/soa/soagen"
 )
 
 func main() {
-	var (
-		in   string
-		out  string
-		name string
-	)
-	flag.StringVar(&in, "in", os.Getenv("GOFILE"), "path to input file (default $GOFILE)")
-	flag.StringVar(&out, "out", "{{dir .}}/{{stem .}}_soa{{ext .}}", "path to output file or - for stdout")
-	flag.StringVar(&name, "name", "{{.}}Slice", "name of generated soa slice")
+	var opts Options
+	flag.StringVar(&opts.In, "in", os.Getenv("GOFILE"), "path to input file (default $GOFILE)")
+	flag.StringVar(&opts.Out, "out", "{{dir .}}/{{stem .}}_soa{{ext .}}", "path to output file or - for stdout")
+	flag.StringVar(&opts.Name, "name", "{{.}}Slice", "name of generated soa slice")
+	flag.BoolVar(&opts.Package, "pkg", false, "load the whole package of the input file with type information")
+	flag.Func("group", "group fields into a column of a struct, i.e. Hot=X,Y (can be repeated)", func(s string) error {
+		name, fields, ok := strings.Cut(s, "=")
+		if !ok || name == "" || fields == "" {
+			return errors.New("must be in the form of name=field,...")
+		}
+		opts.Groups = append(opts.Groups, soagen.Group{Name: name, Fields: strings.Split(fields, ",")})
+		return nil
+	})
+	flag.BoolVar(&opts.Flatten, "flatten", false, "store nested struct fields in columns of their fields recursively")
+	flag.BoolVar(&opts.Split, "split", false, "store fixed-size array fields of up to 16 elements in columns of their elements")
+	flag.IntVar(&opts.BlockSize, "block", 0, "store elements in blocks of the size in each column (array of structures of arrays)")
+	flag.StringVar(&opts.JSON, "json", "", "generate MarshalJSON and UnmarshalJSON in the form of columns or rows")
+	flag.BoolVar(&opts.Binary, "binary", false, "generate MarshalBinary, UnmarshalBinary, WriteTo, and ReadFrom in a compact binary columnar format")
+	flag.BoolVar(&opts.Arrow, "arrow", false, "generate WriteArrow and ReadArrow in the Arrow IPC streaming format")
+	flag.BoolVar(&opts.SQL, "sql", false, "generate ScanRows which appends the rows of database/sql")
+	flag.Func("template", "path to a template which replaces the default template", func(s string) error {
+		b, err := os.ReadFile(s)
+		if err != nil {
+			return err
+		}
+		opts.Template = string(b)
+		return nil
+	})
+	flag.Func("extra", "path to an extra template appended to the output (can be repeated)", func(s string) error {
+		b, err := os.ReadFile(s)
+		if err != nil {
+			return err
+		}
+		opts.Extras = append(opts.Extras, soagen.Extra{Name: filepath.Base(s), Text: string(b)})
+		return nil
+	})
+	flag.BoolVar(&opts.Test, "test", false, "also write the test of the output file to the output file name with _test")
+	flag.BoolVar(&opts.Check, "check", false, "print the diff and fail if the output file is not up to date instead of writing it")
 	flag.Parse()
+	opts.Targets = flag.Args()
 
-	if err := Generate(in, out, name, flag.Args()...); err != nil {
+	if err := Generate(opts); err != nil {
 		log.Fatal(err)
 	}
 }
 
-func Generate(in, out, name string, target ...string) error {
-	f, err := gen.ParseFile(in, target...)
+// Options are the options of Generate.
+type Options struct {
+	soagen.Options
+	// Out is the template of the path to the output file or - for stdout.
+	Out string
+	// Check compares the generated code with the output file without writing it. If they differ, Generate prints the
+	// unified diff and returns ErrStale.
+	Check bool
+}
+
+// ErrStale is returned by Generate in check mode if the output file is not up to date.
+var ErrStale = errors.New("output file is not up to date")
+
+// Generate generates SoA slices and writes them to the output file. Diagnostics are printed to stderr. Warnings don't
+// prevent the generation.
+func Generate(opts Options) error {
+	r, err := soagen.Generate(opts.Options)
 	if err != nil {
 		return err
 	}
+	for _, d := range r.Diagnostics {
+		fmt.Fprintln(os.Stderr, d)
+	}
+	if n := r.Errors(); n > 0 {
+		return fmt.Errorf("%d problem(s) found in %s", n, opts.In)
+	}
 
-	n, err := template.New("").Parse(name)
-	if err != nil {
+	if opts.Out == "-" {
+		if opts.Check {
+			return errors.New("check requires an output file")
+		}
+		if opts.Test {
+			return errors.New("test requires an output file")
+		}
+		_, err := os.Stdout.Write(r.Source)
 		return err
 	}
 
+	o, err := template.New("").Funcs(map[string]any{
+		"dir": filepath.Dir,
+		"stem": func(in string) string {
+			base := filepath.Base(in)
+			return strings.TrimSuffix(base, filepath.Ext(base))
+		},
+		"ext": filepath.Ext,
+	}).Parse(opts.Out)
+	if err != nil {
+		return err
+	}
 	var sb strings.Builder
-	for i := range f.Structs {
-		s := &f.Structs[i]
-
-		sb.Reset()
-		if err := n.Execute(&sb, s.Name); err != nil {
-			return err
-		}
+	if err := o.Execute(&sb, opts.In); err != nil {
+		return err
+	}
+	out := filepath.Clean(sb.String())
 
-		s.SliceName = sb.String()
+	type file struct {
+		path string
+		src  []byte
+	}
+	files := []file{{path: out, src: r.Source}}
+	if opts.Test {
+		// i.e. point_soa.go -> point_soa_test.go
+		files = append(files, file{path: strings.TrimSuffix(out, ".go") + "_test.go", src: r.Test})
 	}
 
-	var w io.Writer
-	if out == "-" {
-		w = os.Stdout
-	} else {
-		o, err := template.New("").Funcs(map[string]any{
-			"dir": filepath.Dir,
-			"stem": func(in string) string {
-				base := filepath.Base(in)
-				return strings.TrimSuffix(base, filepath.Ext(base))
-			},
-			"ext": filepath.Ext,
-		}).Parse(out)
-		if err != nil {
-			return err
-		}
-		var sb strings.Builder
-		if err := o.Execute(&sb, in); err != nil {
-			return err
+	var stale []error
+	for _, f := range files {
+		if !opts.Check {
+			if err := os.WriteFile(f.path, f.src, 0666); err != nil {
+				return err
+			}
+			continue
 		}
-		out = filepath.Clean(sb.String())
 
-		f, err := os.Create(out)
-		if err != nil {
+		// A missing output file is as stale as an outdated one.
+		old, err := os.ReadFile(f.path)
+		if err != nil && !errors.Is(err, fs.ErrNotExist) {
 			return err
 		}
-		defer f.Close()
-		w = f
+		if d := diff.Unified(f.path, f.path, old, f.src); d != "" {
+			fmt.Print(d)
+			stale = append(stale, fmt.Errorf("%s: %w", f.path, ErrStale))
+		}
 	}
-
-	_, err = f.WriteTo(w)
-	return err
+	return errors.Join(stale...)
 }
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ichiban/soa/soagen"
+)
+
+const src = `package point
+
+type Point struct {
+	X, Y int
+}
+`
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		title string
+		// prepare modifies the output files generated in dir before the check.
+		prepare func(t *testing.T, dir string)
+		stale   []string
+	}{
+		{
+			title:   "up to date",
+			prepare: func(t *testing.T, dir string) {},
+		},
+		{
+			title: "stale source",
+			prepare: func(t *testing.T, dir string) {
+				appendFile(t, filepath.Join(dir, "point_soa.go"), "// edited\n")
+			},
+			stale: []string{"point_soa.go"},
+		},
+		{
+			title: "stale test",
+			prepare: func(t *testing.T, dir string) {
+				appendFile(t, filepath.Join(dir, "point_soa_test.go"), "// edited\n")
+			},
+			stale: []string{"point_soa_test.go"},
+		},
+		{
+			title: "stale source and test",
+			prepare: func(t *testing.T, dir string) {
+				appendFile(t, filepath.Join(dir, "point_soa.go"), "// edited\n")
+				appendFile(t, filepath.Join(dir, "point_soa_test.go"), "// edited\n")
+			},
+			stale: []string{"point_soa.go", "point_soa_test.go"},
+		},
+		{
+			title: "missing test",
+			prepare: func(t *testing.T, dir string) {
+				if err := os.Remove(filepath.Join(dir, "point_soa_test.go")); err != nil {
+					t.Fatal(err)
+				}
+			},
+			stale: []string{"point_soa_test.go"},
+		},
+		{
+			title: "missing source and test",
+			prepare: func(t *testing.T, dir string) {
+				for _, f := range []string{"point_soa.go", "point_soa_test.go"} {
+					if err := os.Remove(filepath.Join(dir, f)); err != nil {
+						t.Fatal(err)
+					}
+				}
+			},
+			stale: []string{"point_soa.go", "point_soa_test.go"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			dir := t.TempDir()
+			in := filepath.Join(dir, "point.go")
+			if err := os.WriteFile(in, []byte(src), 0666); err != nil {
+				t.Fatal(err)
+			}
+			opts := Options{
+				Options: soagen.Options{In: in, Name: "{{.}}Slice", Test: true},
+				Out:     "{{dir .}}/{{stem .}}_soa{{ext .}}",
+			}
+			if err := Generate(opts); err != nil {
+				t.Fatal(err)
+			}
+			test.prepare(t, dir)
+
+			before := readDir(t, dir)
+			opts.Check = true
+			err := Generate(opts)
+			if len(test.stale) == 0 {
+				if err != nil {
+					t.Errorf("got %v, want no error", err)
+				}
+			} else {
+				if !errors.Is(err, ErrStale) {
+					t.Fatalf("got %v, want %v", err, ErrStale)
+				}
+				var paths []string
+				for _, e := range err.(interface{ Unwrap() []error }).Unwrap() {
+					path, _, _ := strings.Cut(e.Error(), ": ")
+					paths = append(paths, filepath.Base(path))
+				}
+				if got, want := strings.Join(paths, ","), strings.Join(test.stale, ","); got != want {
+					t.Errorf("got %s, want %s", got, want)
+				}
+			}
+			if after := readDir(t, dir); after != before {
+				t.Errorf("check modified the files: %s, want %s", after, before)
+			}
+		})
+	}
+}
+
+func TestGenerate_stdout(t *testing.T) {
+	tests := []struct {
+		title string
+		opts  Options
+	}{
+		{title: "check", opts: Options{Out: "-", Check: true}},
+		{title: "test", opts: Options{Out: "-", Options: soagen.Options{Test: true}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			dir := t.TempDir()
+			in := filepath.Join(dir, "point.go")
+			if err := os.WriteFile(in, []byte(src), 0666); err != nil {
+				t.Fatal(err)
+			}
+			test.opts.In = in
+			test.opts.Name = "{{.}}Slice"
+			if err := Generate(test.opts); err == nil {
+				t.Error("got no error for the output to stdout")
+			}
+		})
+	}
+}
+
+func appendFile(t *testing.T, path, s string) {
+	t.Helper()
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if _, err := f.WriteString(s); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// readDir returns the names and the contents of the files in dir.
+func readDir(t *testing.T, dir string) string {
+	t.Helper()
+	es, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var sb strings.Builder
+	for _, e := range es {
+		b, err := os.ReadFile(filepath.Join(dir, e.Name()))
+		if err != nil {
+			t.Fatal(err)
+		}
+		sb.WriteString(e.Name())
+		sb.WriteString("\n")
+		sb.Write(b)
+	}
+	return sb.String()
+}
@@ -0,0 +1,170 @@
+package soa
+
+import (
+	"fmt"
+	"reflect"
+	"unsafe"
+)
+
+// Dynamic is a Slice of a struct E which stores each field of E in a column allocated at runtime with reflection.
+// It's slower than SoA slices generated by soagen but lets you try SoA on any struct without code generation.
+// Fields tagged with `soa:"-"` are skipped. The zero value is an empty slice ready to use.
+type Dynamic[E any] struct {
+	// fields are the indices of the fields of E stored in cols.
+	fields []int
+	// cols are slices of the field types, i.e. []int, of the same length and capacity.
+	cols []reflect.Value
+
+	len, cap int
+}
+
+var _ Slice[Dynamic[struct{}], struct{}] = Dynamic[struct{}]{}
+
+// Of returns an empty Dynamic for a struct E. It panics if E is not a struct.
+func Of[E any]() Dynamic[E] {
+	return Dynamic[E]{}.init()
+}
+
+// init allocates empty columns for the fields of E if they're not allocated yet.
+func (s Dynamic[E]) init() Dynamic[E] {
+	if s.cols != nil {
+		return s
+	}
+	t := reflect.TypeFor[E]()
+	if t.Kind() != reflect.Struct {
+		panic(fmt.Sprintf("soa: %s is not a struct", t))
+	}
+	s.cols = make([]reflect.Value, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		if f.Tag.Get("soa") == "-" {
+			continue
+		}
+		s.fields = append(s.fields, i)
+		s.cols = append(s.cols, reflect.MakeSlice(reflect.SliceOf(f.Type), 0, 0))
+	}
+	return s
+}
+
+// Get gets the value of the index. i.e. s[n]
+func (s Dynamic[E]) Get(i int) E {
+	s.check(i)
+	var e E
+	v := reflect.ValueOf(&e).Elem()
+	for j, c := range s.cols {
+		field(v, s.fields[j]).Set(c.Index(i))
+	}
+	return e
+}
+
+// Set sets the value of the index. i.e. s[n] = v
+func (s Dynamic[E]) Set(i int, e E) {
+	s.check(i)
+	v := reflect.ValueOf(&e).Elem()
+	for j, c := range s.cols {
+		c.Index(i).Set(field(v, s.fields[j]))
+	}
+}
+
+// Len returns the length of the slice. i.e. len(s)
+func (s Dynamic[E]) Len() int {
+	return s.len
+}
+
+// Cap returns the capacity of the slice. i.e. cap(s)
+func (s Dynamic[E]) Cap() int {
+	return s.cap
+}
+
+// Slice i.e. s[low:high:max]
+func (s Dynamic[E]) Slice(low, high, max int) Dynamic[E] {
+	if low < 0 || high < low || max < high || s.cap < max {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d:%d] with capacity %d", low, high, max, s.cap))
+	}
+	// A slice of the zero value has to have the columns to grow later.
+	s = s.init()
+	t := Dynamic[E]{
+		fields: s.fields,
+		cols:   make([]reflect.Value, len(s.cols)),
+		len:    high - low,
+		cap:    max - low,
+	}
+	for j, c := range s.cols {
+		t.cols[j] = c.Slice3(low, high, max)
+	}
+	return t
+}
+
+// Grow grows the capacity of the slice to guarantee space for another n elements.
+func (s Dynamic[E]) Grow(n int) Dynamic[E] {
+	if n < 0 {
+		panic("cannot be negative")
+	}
+	s = s.init()
+	if s.len+n <= s.cap {
+		return s
+	}
+	t := Dynamic[E]{
+		fields: s.fields,
+		cols:   make([]reflect.Value, len(s.cols)),
+		len:    s.len,
+		cap:    max(s.len+n, 2*s.cap),
+	}
+	for j, c := range s.cols {
+		t.cols[j] = reflect.MakeSlice(c.Type(), s.len, t.cap)
+		reflect.Copy(t.cols[j], c)
+	}
+	return t
+}
+
+// Swap swaps the elements of the indices. i.e. s[i], s[j] = s[j], s[i]
+func (s Dynamic[E]) Swap(i, j int) {
+	s.check(i)
+	s.check(j)
+	// The fields of a temporary element hold the values of the columns in between.
+	var e E
+	v := reflect.ValueOf(&e).Elem()
+	for k, c := range s.cols {
+		x, y, t := c.Index(i), c.Index(j), field(v, s.fields[k])
+		t.Set(x)
+		x.Set(y)
+		y.Set(t)
+	}
+}
+
+// CopyWithin copies n elements from src to dst even if they overlap. i.e. copy(s[dst:dst+n], s[src:src+n])
+func (s Dynamic[E]) CopyWithin(dst, src, n int) {
+	if dst < 0 || src < 0 || n < 0 || s.cap-n < dst || s.cap-n < src {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d] or [%d:%d] with capacity %d", dst, dst+n, src, src+n, s.cap))
+	}
+	for _, c := range s.cols {
+		reflect.Copy(c.Slice(dst, dst+n), c.Slice(src, src+n))
+	}
+}
+
+// Column returns the column of the field name, i.e. []int, so that you can process a field without Get. It returns nil
+// if the field isn't stored in a column.
+func (s Dynamic[E]) Column(name string) any {
+	t := reflect.TypeFor[E]()
+	for j, c := range s.cols {
+		if t.Field(s.fields[j]).Name == name {
+			return c.Interface()
+		}
+	}
+	return nil
+}
+
+func (s Dynamic[E]) check(i int) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+}
+
+// field returns the settable i-th field of an addressable struct v even if it's unexported.
+func field(v reflect.Value, i int) reflect.Value {
+	f := v.Field(i)
+	if !f.CanSet() {
+		f = reflect.NewAt(f.Type(), unsafe.Pointer(f.UnsafeAddr())).Elem()
+	}
+	return f
+}
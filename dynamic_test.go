@@ -0,0 +1,112 @@
+package soa
+
+import (
+	"reflect"
+	"slices"
+	"testing"
+)
+
+type Account struct {
+	ID      int
+	Name    string
+	Tags    []string
+	balance float64
+	cache   map[string]int `soa:"-"`
+}
+
+func TestOf(t *testing.T) {
+	tests := []struct {
+		title  string
+		of     func() any
+		panics bool
+	}{
+		{title: "struct", of: func() any { return Of[Account]() }},
+		{title: "empty struct", of: func() any { return Of[struct{}]() }},
+		{title: "not struct", of: func() any { return Of[int]() }, panics: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			defer func() {
+				r := recover()
+				if (r != nil) != test.panics {
+					t.Errorf("Of didn't panic: %v", test.panics)
+				}
+			}()
+			_ = test.of()
+		})
+	}
+}
+
+func TestDynamic(t *testing.T) {
+	accounts := []Account{
+		{ID: 3, Name: "Charlie", balance: 3},
+		{ID: 1, Name: "Alice", Tags: []string{"admin"}, balance: 1},
+		{ID: 2, Name: "Bob", balance: 2, cache: map[string]int{"x": 1}},
+	}
+	want := slices.Clone(accounts)
+	want[2].cache = nil
+
+	// The zero value is ready to use.
+	var s Dynamic[Account]
+	s = Append(s, accounts...)
+	if got := ToSlice(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("Append didn't match: %v != %v", got, want)
+	}
+
+	s = Insert(s, 1, Account{ID: 4, Name: "Dan"})
+	s = Delete(s, 1, 2)
+	SortFunc(s, func(a, b Account) int {
+		return a.ID - b.ID
+	})
+	slices.SortFunc(want, func(a, b Account) int {
+		return a.ID - b.ID
+	})
+	if got := ToSlice(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("SortFunc didn't match: %v != %v", got, want)
+	}
+
+	if got := s.Column("Name"); !reflect.DeepEqual(got, []string{"Alice", "Bob", "Charlie"}) {
+		t.Errorf("Column didn't match: %v", got)
+	}
+	if got := s.Column("cache"); got != nil {
+		t.Errorf("Column of skipped field: %v", got)
+	}
+
+	c := Clip(s.Slice(1, 2, 3))
+	if c.Len() != 1 || c.Cap() != 1 || c.Get(0).Name != "Bob" {
+		t.Errorf("Slice didn't match: %v", ToSlice(c))
+	}
+	c = Append(c, Account{ID: 5})
+	if s.Get(2).ID != 3 {
+		t.Error("Append after Clip overwrote the original")
+	}
+
+	// A slice of the zero value is ready to use as well.
+	var z Dynamic[Account]
+	z = Append(z.Slice(0, 0, 0), Account{ID: 6})
+	if got := z.Get(0).ID; got != 6 {
+		t.Errorf("Append to a slice of the zero value didn't match: %d", got)
+	}
+}
+
+func TestDynamic_Get(t *testing.T) {
+	s := Make[Dynamic[Account]](1, 1)
+	defer func() {
+		if recover() == nil {
+			t.Error("Get didn't panic")
+		}
+	}()
+	_ = s.Get(1)
+}
+
+func BenchmarkDynamic_Swap(b *testing.B) {
+	const numAccounts = 10_000
+
+	s := Make[Dynamic[Account]](numAccounts, numAccounts)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		Reverse(s)
+	}
+}
@@ -0,0 +1,386 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"iter"
+
+	"github.com/ichiban/soa/soaarrow"
+	"github.com/ichiban/soa/soabin"
+)
+
+type CellSlice struct {
+	ID    [][4]int64
+	Label [][4]string
+	State [][4]CellSliceState
+
+	off, len, cap int
+}
+
+type CellSliceState struct {
+	Open  bool
+	Depth uint16
+}
+
+type CellSliceRef struct {
+	ID    *int64
+	Label *string
+	State *CellSliceState
+}
+
+func (s CellSlice) Get(i int) Cell {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	var t Cell
+	t.ID = s.ID[(s.off+i)/4][(s.off+i)%4]
+	t.Label = s.Label[(s.off+i)/4][(s.off+i)%4]
+	t.Open = s.State[(s.off+i)/4][(s.off+i)%4].Open
+	t.Depth = s.State[(s.off+i)/4][(s.off+i)%4].Depth
+	return t
+}
+
+func (s CellSlice) Set(i int, t Cell) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.ID[(s.off+i)/4][(s.off+i)%4] = t.ID
+	s.Label[(s.off+i)/4][(s.off+i)%4] = t.Label
+	s.State[(s.off+i)/4][(s.off+i)%4] = CellSliceState{
+		Open:  t.Open,
+		Depth: t.Depth,
+	}
+}
+
+func CellSliceFromSlice(es []Cell) CellSlice {
+	var s CellSlice
+	s = s.Grow(len(es)).Slice(0, len(es), len(es))
+	for i := range es {
+		s.ID[(s.off+i)/4][(s.off+i)%4] = es[i].ID
+	}
+	for i := range es {
+		s.Label[(s.off+i)/4][(s.off+i)%4] = es[i].Label
+	}
+	for i := range es {
+		s.State[(s.off+i)/4][(s.off+i)%4] = CellSliceState{
+			Open:  es[i].Open,
+			Depth: es[i].Depth,
+		}
+	}
+	return s
+}
+
+func (s CellSlice) ToSlice() []Cell {
+	es := make([]Cell, s.Len())
+	for i := range es {
+		es[i].ID = s.ID[(s.off+i)/4][(s.off+i)%4]
+	}
+	for i := range es {
+		es[i].Label = s.Label[(s.off+i)/4][(s.off+i)%4]
+	}
+	for i := range es {
+		c := s.State[(s.off+i)/4][(s.off+i)%4]
+		es[i].Open = c.Open
+		es[i].Depth = c.Depth
+	}
+	return es
+}
+
+func (s CellSlice) Ref(i int) CellSliceRef {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return CellSliceRef{
+		ID:    &s.ID[(s.off+i)/4][(s.off+i)%4],
+		Label: &s.Label[(s.off+i)/4][(s.off+i)%4],
+		State: &s.State[(s.off+i)/4][(s.off+i)%4],
+	}
+}
+
+func (s CellSlice) Refs() iter.Seq2[int, CellSliceRef] {
+	return func(yield func(int, CellSliceRef) bool) {
+		for i := 0; i < s.Len(); i++ {
+			if !yield(i, s.Ref(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (s CellSlice) GetID(i int) int64 {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return s.ID[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s CellSlice) SetID(i int, v int64) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.ID[(s.off+i)/4][(s.off+i)%4] = v
+}
+
+func (s CellSlice) IDSeq() iter.Seq2[int, int64] {
+	return func(yield func(int, int64) bool) {
+		for i := 0; i < s.len; i++ {
+			if !yield(i, s.ID[(s.off+i)/4][(s.off+i)%4]) {
+				return
+			}
+		}
+	}
+}
+
+func (s CellSlice) GetLabel(i int) string {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return s.Label[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s CellSlice) SetLabel(i int, v string) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.Label[(s.off+i)/4][(s.off+i)%4] = v
+}
+
+func (s CellSlice) LabelSeq() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i := 0; i < s.len; i++ {
+			if !yield(i, s.Label[(s.off+i)/4][(s.off+i)%4]) {
+				return
+			}
+		}
+	}
+}
+
+func (s CellSlice) GetState(i int) CellSliceState {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return s.State[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s CellSlice) SetState(i int, v CellSliceState) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.State[(s.off+i)/4][(s.off+i)%4] = v
+}
+
+func (s CellSlice) StateSeq() iter.Seq2[int, CellSliceState] {
+	return func(yield func(int, CellSliceState) bool) {
+		for i := 0; i < s.len; i++ {
+			if !yield(i, s.State[(s.off+i)/4][(s.off+i)%4]) {
+				return
+			}
+		}
+	}
+}
+
+func (s CellSlice) Len() int {
+	return s.len
+}
+
+func (s CellSlice) Cap() int {
+	return s.cap
+}
+
+func (s CellSlice) Slice(low, high, max int) CellSlice {
+	if low < 0 || high < low || max < high || s.cap < max {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d:%d] with capacity %d", low, high, max, s.cap))
+	}
+	off := s.off + low
+	b, e := off/4, (s.off+max+4-1)/4
+	return CellSlice{
+		ID:    s.ID[b:e:e],
+		Label: s.Label[b:e:e],
+		State: s.State[b:e:e],
+		off:   off % 4,
+		len:   high - low,
+		cap:   max - low,
+	}
+}
+
+func (s CellSlice) Grow(n int) CellSlice {
+	if n < 0 {
+		panic("cannot be negative")
+	}
+	if s.len+n <= s.cap {
+		return s
+	}
+	m := max((s.off+s.len+n+4-1)/4, 2*len(s.ID))
+	t := CellSlice{
+		ID:    make([][4]int64, m),
+		Label: make([][4]string, m),
+		State: make([][4]CellSliceState, m),
+		off:   s.off,
+		len:   s.len,
+		cap:   m*4 - s.off,
+	}
+	copy(t.ID, s.ID)
+	copy(t.Label, s.Label)
+	copy(t.State, s.State)
+	return t
+}
+
+func (s CellSlice) Swap(i, j int) {
+	for _, k := range [...]int{i, j} {
+		if uint(k) >= uint(s.len) {
+			panic(fmt.Sprintf("index out of range [%d] with length %d", k, s.len))
+		}
+	}
+	s.ID[(s.off+i)/4][(s.off+i)%4], s.ID[(s.off+j)/4][(s.off+j)%4] = s.ID[(s.off+j)/4][(s.off+j)%4], s.ID[(s.off+i)/4][(s.off+i)%4]
+	s.Label[(s.off+i)/4][(s.off+i)%4], s.Label[(s.off+j)/4][(s.off+j)%4] = s.Label[(s.off+j)/4][(s.off+j)%4], s.Label[(s.off+i)/4][(s.off+i)%4]
+	s.State[(s.off+i)/4][(s.off+i)%4], s.State[(s.off+j)/4][(s.off+j)%4] = s.State[(s.off+j)/4][(s.off+j)%4], s.State[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s CellSlice) CopyWithin(dst, src, n int) {
+	if dst < 0 || src < 0 || n < 0 || s.cap-n < dst || s.cap-n < src {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d] or [%d:%d] with capacity %d", dst, dst+n, src, src+n, s.cap))
+	}
+	if dst < src {
+		for k := 0; k < n; k++ {
+			s.ID[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.ID[(s.off+src+k)/4][(s.off+src+k)%4]
+			s.Label[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.Label[(s.off+src+k)/4][(s.off+src+k)%4]
+			s.State[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.State[(s.off+src+k)/4][(s.off+src+k)%4]
+		}
+		return
+	}
+	for k := n - 1; k >= 0; k-- {
+		s.ID[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.ID[(s.off+src+k)/4][(s.off+src+k)%4]
+		s.Label[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.Label[(s.off+src+k)/4][(s.off+src+k)%4]
+		s.State[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.State[(s.off+src+k)/4][(s.off+src+k)%4]
+	}
+}
+
+// MarshalBinary encodes the SoA slice in the format of soabin.
+func (s CellSlice) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := s.WriteTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes the SoA slice in the format of soabin.
+func (s *CellSlice) UnmarshalBinary(b []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(b))
+	return err
+}
+
+// WriteTo writes the SoA slice in the format of soabin.
+func (s CellSlice) WriteTo(w io.Writer) (int64, error) {
+	n := s.Len()
+	cID := make([]int64, n)
+	for i := range n {
+		cID[i] = s.ID[(s.off+i)/4][(s.off+i)%4]
+	}
+	cLabel := make([]string, n)
+	for i := range n {
+		cLabel[i] = s.Label[(s.off+i)/4][(s.off+i)%4]
+	}
+	cOpen := make([]bool, n)
+	for i := range n {
+		cOpen[i] = s.State[(s.off+i)/4][(s.off+i)%4].Open
+	}
+	cDepth := make([]uint16, n)
+	for i := range n {
+		cDepth[i] = s.State[(s.off+i)/4][(s.off+i)%4].Depth
+	}
+	return soabin.Encode(w, n, soabin.Col("ID", cID), soabin.Col("Label", cLabel), soabin.Col("Open", cOpen), soabin.Col("Depth", cDepth))
+}
+
+// ReadFrom reads the SoA slice in the format of soabin.
+func (s *CellSlice) ReadFrom(r io.Reader) (int64, error) {
+	d := soabin.NewDecoder(r)
+	n, err := d.Len()
+	if err != nil {
+		return d.Count(), err
+	}
+	var (
+		cID    []int64
+		cLabel []string
+		cOpen  []bool
+		cDepth []uint16
+	)
+	if err := d.Decode(soabin.Into("ID", &cID), soabin.Into("Label", &cLabel), soabin.Into("Open", &cOpen), soabin.Into("Depth", &cDepth)); err != nil {
+		return d.Count(), err
+	}
+	var t CellSlice
+	*s = t.Grow(n).Slice(0, n, n)
+	for i := range n {
+		s.ID[(s.off+i)/4][(s.off+i)%4] = cID[i]
+	}
+	for i := range n {
+		s.Label[(s.off+i)/4][(s.off+i)%4] = cLabel[i]
+	}
+	for i := range n {
+		s.State[(s.off+i)/4][(s.off+i)%4].Open = cOpen[i]
+		s.State[(s.off+i)/4][(s.off+i)%4].Depth = cDepth[i]
+	}
+	return d.Count(), nil
+}
+
+// WriteArrow writes the SoA slice as a record batch in the Arrow IPC streaming format.
+func (s CellSlice) WriteArrow(w io.Writer) error {
+	n := s.Len()
+	cID := make([]int64, n)
+	for i := range n {
+		cID[i] = s.ID[(s.off+i)/4][(s.off+i)%4]
+	}
+	cLabel := make([]string, n)
+	for i := range n {
+		cLabel[i] = s.Label[(s.off+i)/4][(s.off+i)%4]
+	}
+	cOpen := make([]bool, n)
+	for i := range n {
+		cOpen[i] = s.State[(s.off+i)/4][(s.off+i)%4].Open
+	}
+	cDepth := make([]uint16, n)
+	for i := range n {
+		cDepth[i] = s.State[(s.off+i)/4][(s.off+i)%4].Depth
+	}
+	return soaarrow.Write(w, n, soaarrow.Col("ID", cID), soaarrow.Col("Label", cLabel), soaarrow.Col("Open", cOpen), soaarrow.Col("Depth", cDepth))
+}
+
+// ReadArrow replaces the SoA slice with the rows of the record batches in the Arrow IPC streaming format.
+func (s *CellSlice) ReadArrow(r io.Reader) error {
+	d := soaarrow.NewReader(r)
+	t := *s
+	*s = CellSlice{}
+	for {
+		n, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			*s = t
+			return err
+		}
+		l := s.Len()
+		*s = s.Grow(n)
+		*s = s.Slice(0, l+n, s.Cap())
+		cID := make([]int64, n)
+		cLabel := make([]string, n)
+		cOpen := make([]bool, n)
+		cDepth := make([]uint16, n)
+		if err := d.Decode(soaarrow.Col("ID", cID), soaarrow.Col("Label", cLabel), soaarrow.Col("Open", cOpen), soaarrow.Col("Depth", cDepth)); err != nil {
+			*s = t
+			return err
+		}
+		for i := range n {
+			s.ID[(s.off+l+i)/4][(s.off+l+i)%4] = cID[i]
+		}
+		for i := range n {
+			s.Label[(s.off+l+i)/4][(s.off+l+i)%4] = cLabel[i]
+		}
+		for i := range n {
+			s.State[(s.off+l+i)/4][(s.off+l+i)%4].Open = cOpen[i]
+			s.State[(s.off+l+i)/4][(s.off+l+i)%4].Depth = cDepth[i]
+		}
+	}
+}
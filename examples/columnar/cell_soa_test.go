@@ -0,0 +1,98 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ichiban/soa"
+	"github.com/ichiban/soa/soabin"
+	"github.com/ichiban/soa/soatest"
+)
+
+var _ soa.Slice[CellSlice, Cell] = CellSlice{}
+
+func TestCellSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	t.Run("round trip", func(t *testing.T) {
+		s := soa.Make[CellSlice](3, 3)
+		for i := range s.Len() {
+			want := soatest.Value[Cell](r)
+			s.Set(i, want)
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.ID, want.ID) {
+				t.Errorf("ID: got %v, want %v", got.ID, want.ID)
+			}
+			if !reflect.DeepEqual(got.Label, want.Label) {
+				t.Errorf("Label: got %v, want %v", got.Label, want.Label)
+			}
+			if !reflect.DeepEqual(got.Open, want.Open) {
+				t.Errorf("Open: got %v, want %v", got.Open, want.Open)
+			}
+			if !reflect.DeepEqual(got.Depth, want.Depth) {
+				t.Errorf("Depth: got %v, want %v", got.Depth, want.Depth)
+			}
+		}
+	})
+
+	t.Run("refs", func(t *testing.T) {
+		s := soa.Make[CellSlice](3, 3)
+		for i, ref := range s.Refs() {
+			want := soatest.Value[Cell](r)
+			*ref.ID = want.ID
+			*ref.Label = want.Label
+			ref.State.Open = want.Open
+			ref.State.Depth = want.Depth
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.ID, want.ID) {
+				t.Errorf("ID: got %v, want %v", got.ID, want.ID)
+			}
+			if !reflect.DeepEqual(got.Label, want.Label) {
+				t.Errorf("Label: got %v, want %v", got.Label, want.Label)
+			}
+			if !reflect.DeepEqual(got.Open, want.Open) {
+				t.Errorf("Open: got %v, want %v", got.Open, want.Open)
+			}
+			if !reflect.DeepEqual(got.Depth, want.Depth) {
+				t.Errorf("Depth: got %v, want %v", got.Depth, want.Depth)
+			}
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		for _, n := range []int{0, 5} {
+			want := make([]Cell, n)
+			for i := range want {
+				want[i] = soatest.Value[Cell](r)
+			}
+			b, err := soa.FromSlice[CellSlice](want).MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var s CellSlice
+			if err := s.UnmarshalBinary(b); err != nil {
+				t.Fatal(err)
+			}
+			if got := soa.ToSlice(s); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		}
+
+		// The header claims more rows than the columns have.
+		var w bytes.Buffer
+		if _, err := soabin.Encode(&w, 1<<30, soabin.Col("ID", []int64{}), soabin.Col("Label", []string{}), soabin.Col("Open", []bool{}), soabin.Col("Depth", []uint16{})); err != nil {
+			t.Fatal(err)
+		}
+		var s CellSlice
+		if err := s.UnmarshalBinary(w.Bytes()); err == nil {
+			t.Error("got no error for the broken number of rows")
+		}
+	})
+
+	soatest.Run[CellSlice](t, func() Cell {
+		return soatest.Value[Cell](r)
+	})
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/ichiban/soa"
+)
+
+// Particle is an example struct encoded in columnar formats.
+type Particle struct {
+	Name  string
+	Alive bool
+	// X and Y are stored in a column of a struct.
+	X    float32 `soa:",group=Pos"`
+	Y    float32 `soa:",group=Pos"`
+	Mass float64
+}
+
+// Cell is an example struct stored in blocks of 4 and encoded in columnar formats.
+type Cell struct {
+	ID    int64
+	Label string
+	// Open and Depth are stored in a column of a struct.
+	Open  bool   `soa:",group=State"`
+	Depth uint16 `soa:",group=State"`
+}
+
+// Reading is an example struct of plain columns encoded in columnar formats.
+type Reading struct {
+	Sensor string
+	Time   int64
+	Value  float64
+}
+
+// To generate the SoA slices, run `go generate ./...`.
+//go:generate go run ../../cmd/soagen -test -binary -arrow -out particle_soa.go Particle
+//go:generate go run ../../cmd/soagen -test -binary -arrow -out reading_soa.go Reading
+//go:generate go run ../../cmd/soagen -test -binary -arrow -block 4 -out cell_soa.go Cell
+
+func main() {
+	s := soa.FromSlice[ParticleSlice]([]Particle{
+		{Name: "a", Alive: true, X: 1, Y: 2, Mass: 0.5},
+		{Name: "b", X: 3, Y: 4, Mass: 1.5},
+	})
+
+	// With `-binary`, ParticleSlice is written column by column.
+	var b bytes.Buffer
+	if _, err := s.WriteTo(&b); err != nil {
+		panic(err)
+	}
+	fmt.Printf("%d bytes\n", b.Len())
+
+	var decoded ParticleSlice
+	if _, err := decoded.ReadFrom(&b); err != nil {
+		panic(err)
+	}
+	for i, p := range soa.All(decoded) {
+		fmt.Println(i, p)
+	}
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/ichiban/soa"
+)
+
+func TestParticleSlice_Arrow(t *testing.T) {
+	want := []Particle{
+		{Name: "a", Alive: true, X: 1, Y: 2, Mass: 0.5},
+		{Name: "", X: -3, Y: 4, Mass: 1.5},
+		{Name: "long name", Alive: true},
+	}
+
+	var b bytes.Buffer
+	if err := soa.FromSlice[ParticleSlice](want).WriteArrow(&b); err != nil {
+		t.Fatal(err)
+	}
+	valid := b.Bytes()
+
+	var s ParticleSlice
+	if err := s.ReadArrow(bytes.NewReader(valid)); err != nil {
+		t.Fatal(err)
+	}
+	if got := soa.ToSlice(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+
+	// A broken stream leaves the SoA slice as it was.
+	if err := s.ReadArrow(bytes.NewReader(valid[:len(valid)-20])); err != io.ErrUnexpectedEOF {
+		t.Errorf("got %v, want %v", err, io.ErrUnexpectedEOF)
+	}
+	if got := soa.ToSlice(s); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v after the error, want %+v", got, want)
+	}
+}
+
+func TestCellSlice_Arrow(t *testing.T) {
+	want := make([]Cell, 10)
+	for i := range want {
+		want[i] = Cell{ID: int64(i), Label: string(rune('a' + i)), Open: i%3 == 0, Depth: uint16(100 * i)}
+	}
+
+	var b bytes.Buffer
+	if err := soa.FromSlice[CellSlice](want).Slice(1, 10, 10).WriteArrow(&b); err != nil {
+		t.Fatal(err)
+	}
+
+	// ReadArrow replaces the SoA slice even if it's a part of another one.
+	s := soa.Make[CellSlice](7, 7).Slice(2, 5, 7)
+	if err := s.ReadArrow(&b); err != nil {
+		t.Fatal(err)
+	}
+	if got := soa.ToSlice(s); !reflect.DeepEqual(got, want[1:]) {
+		t.Errorf("got %+v, want %+v", got, want[1:])
+	}
+}
@@ -0,0 +1,349 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"bytes"
+	"io"
+	"iter"
+	"slices"
+
+	"github.com/ichiban/soa/soaarrow"
+	"github.com/ichiban/soa/soabin"
+)
+
+type ParticleSlice struct {
+	Name  []string
+	Alive []bool
+	Pos   []ParticleSlicePos
+	Mass  []float64
+}
+
+type ParticleSlicePos struct {
+	X float32
+	Y float32
+}
+
+type ParticleSliceRef struct {
+	Name  *string
+	Alive *bool
+	Pos   *ParticleSlicePos
+	Mass  *float64
+}
+
+func (s ParticleSlice) Get(i int) Particle {
+	var t Particle
+	t.Name = s.Name[i]
+	t.Alive = s.Alive[i]
+	t.X = s.Pos[i].X
+	t.Y = s.Pos[i].Y
+	t.Mass = s.Mass[i]
+	return t
+}
+
+func (s ParticleSlice) Set(i int, t Particle) {
+	s.Name[i] = t.Name
+	s.Alive[i] = t.Alive
+	s.Pos[i] = ParticleSlicePos{
+		X: t.X,
+		Y: t.Y,
+	}
+	s.Mass[i] = t.Mass
+}
+
+func ParticleSliceFromSlice(es []Particle) ParticleSlice {
+	var s ParticleSlice
+	s = s.Grow(len(es)).Slice(0, len(es), len(es))
+	for i := range es {
+		s.Name[i] = es[i].Name
+	}
+	for i := range es {
+		s.Alive[i] = es[i].Alive
+	}
+	for i := range es {
+		s.Pos[i] = ParticleSlicePos{
+			X: es[i].X,
+			Y: es[i].Y,
+		}
+	}
+	for i := range es {
+		s.Mass[i] = es[i].Mass
+	}
+	return s
+}
+
+func (s ParticleSlice) ToSlice() []Particle {
+	es := make([]Particle, s.Len())
+	for i := range es {
+		es[i].Name = s.Name[i]
+	}
+	for i := range es {
+		es[i].Alive = s.Alive[i]
+	}
+	for i := range es {
+		c := s.Pos[i]
+		es[i].X = c.X
+		es[i].Y = c.Y
+	}
+	for i := range es {
+		es[i].Mass = s.Mass[i]
+	}
+	return es
+}
+
+func (s ParticleSlice) Ref(i int) ParticleSliceRef {
+	return ParticleSliceRef{
+		Name:  &s.Name[i],
+		Alive: &s.Alive[i],
+		Pos:   &s.Pos[i],
+		Mass:  &s.Mass[i],
+	}
+}
+
+func (s ParticleSlice) Refs() iter.Seq2[int, ParticleSliceRef] {
+	return func(yield func(int, ParticleSliceRef) bool) {
+		for i := 0; i < s.Len(); i++ {
+			if !yield(i, s.Ref(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (s ParticleSlice) GetName(i int) string {
+	return s.Name[i]
+}
+
+func (s ParticleSlice) SetName(i int, v string) {
+	s.Name[i] = v
+}
+
+func (s ParticleSlice) NameSeq() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i, v := range s.Name[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s ParticleSlice) GetAlive(i int) bool {
+	return s.Alive[i]
+}
+
+func (s ParticleSlice) SetAlive(i int, v bool) {
+	s.Alive[i] = v
+}
+
+func (s ParticleSlice) AliveSeq() iter.Seq2[int, bool] {
+	return func(yield func(int, bool) bool) {
+		for i, v := range s.Alive[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s ParticleSlice) GetPos(i int) ParticleSlicePos {
+	return s.Pos[i]
+}
+
+func (s ParticleSlice) SetPos(i int, v ParticleSlicePos) {
+	s.Pos[i] = v
+}
+
+func (s ParticleSlice) PosSeq() iter.Seq2[int, ParticleSlicePos] {
+	return func(yield func(int, ParticleSlicePos) bool) {
+		for i, v := range s.Pos[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s ParticleSlice) GetMass(i int) float64 {
+	return s.Mass[i]
+}
+
+func (s ParticleSlice) SetMass(i int, v float64) {
+	s.Mass[i] = v
+}
+
+func (s ParticleSlice) MassSeq() iter.Seq2[int, float64] {
+	return func(yield func(int, float64) bool) {
+		for i, v := range s.Mass[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s ParticleSlice) Len() int {
+	return min(
+		len(s.Name),
+		len(s.Alive),
+		len(s.Pos),
+		len(s.Mass),
+	)
+}
+
+func (s ParticleSlice) Cap() int {
+	return min(
+		cap(s.Name),
+		cap(s.Alive),
+		cap(s.Pos),
+		cap(s.Mass),
+	)
+}
+
+func (s ParticleSlice) Slice(low, high, max int) ParticleSlice {
+	return ParticleSlice{
+		Name:  s.Name[low:high:max],
+		Alive: s.Alive[low:high:max],
+		Pos:   s.Pos[low:high:max],
+		Mass:  s.Mass[low:high:max],
+	}
+}
+
+func (s ParticleSlice) Grow(n int) ParticleSlice {
+	return ParticleSlice{
+		Name:  slices.Grow(s.Name, n),
+		Alive: slices.Grow(s.Alive, n),
+		Pos:   slices.Grow(s.Pos, n),
+		Mass:  slices.Grow(s.Mass, n),
+	}
+}
+
+func (s ParticleSlice) Columns() ([]string, []bool, []ParticleSlicePos, []float64) {
+	n := s.Len()
+	return s.Name[:n], s.Alive[:n], s.Pos[:n], s.Mass[:n]
+}
+
+func (s ParticleSlice) Swap(i, j int) {
+	s.Name[i], s.Name[j] = s.Name[j], s.Name[i]
+	s.Alive[i], s.Alive[j] = s.Alive[j], s.Alive[i]
+	s.Pos[i], s.Pos[j] = s.Pos[j], s.Pos[i]
+	s.Mass[i], s.Mass[j] = s.Mass[j], s.Mass[i]
+}
+
+func (s ParticleSlice) CopyWithin(dst, src, n int) {
+	copy(s.Name[dst:dst+n], s.Name[src:src+n])
+	copy(s.Alive[dst:dst+n], s.Alive[src:src+n])
+	copy(s.Pos[dst:dst+n], s.Pos[src:src+n])
+	copy(s.Mass[dst:dst+n], s.Mass[src:src+n])
+}
+
+// MarshalBinary encodes the SoA slice in the format of soabin.
+func (s ParticleSlice) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := s.WriteTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes the SoA slice in the format of soabin.
+func (s *ParticleSlice) UnmarshalBinary(b []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(b))
+	return err
+}
+
+// WriteTo writes the SoA slice in the format of soabin.
+func (s ParticleSlice) WriteTo(w io.Writer) (int64, error) {
+	n := s.Len()
+	cName := s.Name[:n]
+	cAlive := s.Alive[:n]
+	cX := make([]float32, n)
+	for i := range n {
+		cX[i] = s.Pos[i].X
+	}
+	cY := make([]float32, n)
+	for i := range n {
+		cY[i] = s.Pos[i].Y
+	}
+	cMass := s.Mass[:n]
+	return soabin.Encode(w, n, soabin.Col("Name", cName), soabin.Col("Alive", cAlive), soabin.Col("X", cX), soabin.Col("Y", cY), soabin.Col("Mass", cMass))
+}
+
+// ReadFrom reads the SoA slice in the format of soabin.
+func (s *ParticleSlice) ReadFrom(r io.Reader) (int64, error) {
+	d := soabin.NewDecoder(r)
+	n, err := d.Len()
+	if err != nil {
+		return d.Count(), err
+	}
+	var (
+		cName  []string
+		cAlive []bool
+		cX     []float32
+		cY     []float32
+		cMass  []float64
+	)
+	if err := d.Decode(soabin.Into("Name", &cName), soabin.Into("Alive", &cAlive), soabin.Into("X", &cX), soabin.Into("Y", &cY), soabin.Into("Mass", &cMass)); err != nil {
+		return d.Count(), err
+	}
+	*s = ParticleSlice{
+		Name:  cName[:n:n],
+		Alive: cAlive[:n:n],
+		Pos:   make([]ParticleSlicePos, n),
+		Mass:  cMass[:n:n],
+	}
+	for i := range n {
+		s.Pos[i].X = cX[i]
+		s.Pos[i].Y = cY[i]
+	}
+	return d.Count(), nil
+}
+
+// WriteArrow writes the SoA slice as a record batch in the Arrow IPC streaming format.
+func (s ParticleSlice) WriteArrow(w io.Writer) error {
+	n := s.Len()
+	cName := s.Name[:n]
+	cAlive := s.Alive[:n]
+	cX := make([]float32, n)
+	for i := range n {
+		cX[i] = s.Pos[i].X
+	}
+	cY := make([]float32, n)
+	for i := range n {
+		cY[i] = s.Pos[i].Y
+	}
+	cMass := s.Mass[:n]
+	return soaarrow.Write(w, n, soaarrow.Col("Name", cName), soaarrow.Col("Alive", cAlive), soaarrow.Col("X", cX), soaarrow.Col("Y", cY), soaarrow.Col("Mass", cMass))
+}
+
+// ReadArrow replaces the SoA slice with the rows of the record batches in the Arrow IPC streaming format.
+func (s *ParticleSlice) ReadArrow(r io.Reader) error {
+	d := soaarrow.NewReader(r)
+	t := *s
+	*s = ParticleSlice{}
+	for {
+		n, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			*s = t
+			return err
+		}
+		l := s.Len()
+		*s = s.Grow(n)
+		*s = s.Slice(0, l+n, s.Cap())
+		cName := s.Name[l : l+n]
+		cAlive := s.Alive[l : l+n]
+		cX := make([]float32, n)
+		cY := make([]float32, n)
+		cMass := s.Mass[l : l+n]
+		if err := d.Decode(soaarrow.Col("Name", cName), soaarrow.Col("Alive", cAlive), soaarrow.Col("X", cX), soaarrow.Col("Y", cY), soaarrow.Col("Mass", cMass)); err != nil {
+			*s = t
+			return err
+		}
+		for i := range n {
+			s.Pos[l+i].X = cX[i]
+			s.Pos[l+i].Y = cY[i]
+		}
+	}
+}
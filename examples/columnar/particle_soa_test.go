@@ -0,0 +1,105 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ichiban/soa"
+	"github.com/ichiban/soa/soabin"
+	"github.com/ichiban/soa/soatest"
+)
+
+var _ soa.Slice[ParticleSlice, Particle] = ParticleSlice{}
+
+func TestParticleSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	t.Run("round trip", func(t *testing.T) {
+		s := soa.Make[ParticleSlice](3, 3)
+		for i := range s.Len() {
+			want := soatest.Value[Particle](r)
+			s.Set(i, want)
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.Name, want.Name) {
+				t.Errorf("Name: got %v, want %v", got.Name, want.Name)
+			}
+			if !reflect.DeepEqual(got.Alive, want.Alive) {
+				t.Errorf("Alive: got %v, want %v", got.Alive, want.Alive)
+			}
+			if !reflect.DeepEqual(got.X, want.X) {
+				t.Errorf("X: got %v, want %v", got.X, want.X)
+			}
+			if !reflect.DeepEqual(got.Y, want.Y) {
+				t.Errorf("Y: got %v, want %v", got.Y, want.Y)
+			}
+			if !reflect.DeepEqual(got.Mass, want.Mass) {
+				t.Errorf("Mass: got %v, want %v", got.Mass, want.Mass)
+			}
+		}
+	})
+
+	t.Run("refs", func(t *testing.T) {
+		s := soa.Make[ParticleSlice](3, 3)
+		for i, ref := range s.Refs() {
+			want := soatest.Value[Particle](r)
+			*ref.Name = want.Name
+			*ref.Alive = want.Alive
+			ref.Pos.X = want.X
+			ref.Pos.Y = want.Y
+			*ref.Mass = want.Mass
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.Name, want.Name) {
+				t.Errorf("Name: got %v, want %v", got.Name, want.Name)
+			}
+			if !reflect.DeepEqual(got.Alive, want.Alive) {
+				t.Errorf("Alive: got %v, want %v", got.Alive, want.Alive)
+			}
+			if !reflect.DeepEqual(got.X, want.X) {
+				t.Errorf("X: got %v, want %v", got.X, want.X)
+			}
+			if !reflect.DeepEqual(got.Y, want.Y) {
+				t.Errorf("Y: got %v, want %v", got.Y, want.Y)
+			}
+			if !reflect.DeepEqual(got.Mass, want.Mass) {
+				t.Errorf("Mass: got %v, want %v", got.Mass, want.Mass)
+			}
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		for _, n := range []int{0, 5} {
+			want := make([]Particle, n)
+			for i := range want {
+				want[i] = soatest.Value[Particle](r)
+			}
+			b, err := soa.FromSlice[ParticleSlice](want).MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var s ParticleSlice
+			if err := s.UnmarshalBinary(b); err != nil {
+				t.Fatal(err)
+			}
+			if got := soa.ToSlice(s); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		}
+
+		// The header claims more rows than the columns have.
+		var w bytes.Buffer
+		if _, err := soabin.Encode(&w, 1<<30, soabin.Col("Name", []string{}), soabin.Col("Alive", []bool{}), soabin.Col("X", []float32{}), soabin.Col("Y", []float32{}), soabin.Col("Mass", []float64{})); err != nil {
+			t.Fatal(err)
+		}
+		var s ParticleSlice
+		if err := s.UnmarshalBinary(w.Bytes()); err == nil {
+			t.Error("got no error for the broken number of rows")
+		}
+	})
+
+	soatest.Run[ParticleSlice](t, func() Particle {
+		return soatest.Value[Particle](r)
+	})
+}
@@ -0,0 +1,271 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"bytes"
+	"io"
+	"iter"
+	"slices"
+
+	"github.com/ichiban/soa/soaarrow"
+	"github.com/ichiban/soa/soabin"
+)
+
+type ReadingSlice struct {
+	Sensor []string
+	Time   []int64
+	Value  []float64
+}
+
+type ReadingSliceRef struct {
+	Sensor *string
+	Time   *int64
+	Value  *float64
+}
+
+func (s ReadingSlice) Get(i int) Reading {
+	var t Reading
+	t.Sensor = s.Sensor[i]
+	t.Time = s.Time[i]
+	t.Value = s.Value[i]
+	return t
+}
+
+func (s ReadingSlice) Set(i int, t Reading) {
+	s.Sensor[i] = t.Sensor
+	s.Time[i] = t.Time
+	s.Value[i] = t.Value
+}
+
+func ReadingSliceFromSlice(es []Reading) ReadingSlice {
+	var s ReadingSlice
+	s = s.Grow(len(es)).Slice(0, len(es), len(es))
+	for i := range es {
+		s.Sensor[i] = es[i].Sensor
+	}
+	for i := range es {
+		s.Time[i] = es[i].Time
+	}
+	for i := range es {
+		s.Value[i] = es[i].Value
+	}
+	return s
+}
+
+func (s ReadingSlice) ToSlice() []Reading {
+	es := make([]Reading, s.Len())
+	for i := range es {
+		es[i].Sensor = s.Sensor[i]
+	}
+	for i := range es {
+		es[i].Time = s.Time[i]
+	}
+	for i := range es {
+		es[i].Value = s.Value[i]
+	}
+	return es
+}
+
+func (s ReadingSlice) Ref(i int) ReadingSliceRef {
+	return ReadingSliceRef{
+		Sensor: &s.Sensor[i],
+		Time:   &s.Time[i],
+		Value:  &s.Value[i],
+	}
+}
+
+func (s ReadingSlice) Refs() iter.Seq2[int, ReadingSliceRef] {
+	return func(yield func(int, ReadingSliceRef) bool) {
+		for i := 0; i < s.Len(); i++ {
+			if !yield(i, s.Ref(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (s ReadingSlice) GetSensor(i int) string {
+	return s.Sensor[i]
+}
+
+func (s ReadingSlice) SetSensor(i int, v string) {
+	s.Sensor[i] = v
+}
+
+func (s ReadingSlice) SensorSeq() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i, v := range s.Sensor[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s ReadingSlice) GetTime(i int) int64 {
+	return s.Time[i]
+}
+
+func (s ReadingSlice) SetTime(i int, v int64) {
+	s.Time[i] = v
+}
+
+func (s ReadingSlice) TimeSeq() iter.Seq2[int, int64] {
+	return func(yield func(int, int64) bool) {
+		for i, v := range s.Time[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s ReadingSlice) GetValue(i int) float64 {
+	return s.Value[i]
+}
+
+func (s ReadingSlice) SetValue(i int, v float64) {
+	s.Value[i] = v
+}
+
+func (s ReadingSlice) ValueSeq() iter.Seq2[int, float64] {
+	return func(yield func(int, float64) bool) {
+		for i, v := range s.Value[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s ReadingSlice) Len() int {
+	return min(
+		len(s.Sensor),
+		len(s.Time),
+		len(s.Value),
+	)
+}
+
+func (s ReadingSlice) Cap() int {
+	return min(
+		cap(s.Sensor),
+		cap(s.Time),
+		cap(s.Value),
+	)
+}
+
+func (s ReadingSlice) Slice(low, high, max int) ReadingSlice {
+	return ReadingSlice{
+		Sensor: s.Sensor[low:high:max],
+		Time:   s.Time[low:high:max],
+		Value:  s.Value[low:high:max],
+	}
+}
+
+func (s ReadingSlice) Grow(n int) ReadingSlice {
+	return ReadingSlice{
+		Sensor: slices.Grow(s.Sensor, n),
+		Time:   slices.Grow(s.Time, n),
+		Value:  slices.Grow(s.Value, n),
+	}
+}
+
+func (s ReadingSlice) Columns() ([]string, []int64, []float64) {
+	n := s.Len()
+	return s.Sensor[:n], s.Time[:n], s.Value[:n]
+}
+
+func (s ReadingSlice) Swap(i, j int) {
+	s.Sensor[i], s.Sensor[j] = s.Sensor[j], s.Sensor[i]
+	s.Time[i], s.Time[j] = s.Time[j], s.Time[i]
+	s.Value[i], s.Value[j] = s.Value[j], s.Value[i]
+}
+
+func (s ReadingSlice) CopyWithin(dst, src, n int) {
+	copy(s.Sensor[dst:dst+n], s.Sensor[src:src+n])
+	copy(s.Time[dst:dst+n], s.Time[src:src+n])
+	copy(s.Value[dst:dst+n], s.Value[src:src+n])
+}
+
+// MarshalBinary encodes the SoA slice in the format of soabin.
+func (s ReadingSlice) MarshalBinary() ([]byte, error) {
+	var b bytes.Buffer
+	if _, err := s.WriteTo(&b); err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
+
+// UnmarshalBinary decodes the SoA slice in the format of soabin.
+func (s *ReadingSlice) UnmarshalBinary(b []byte) error {
+	_, err := s.ReadFrom(bytes.NewReader(b))
+	return err
+}
+
+// WriteTo writes the SoA slice in the format of soabin.
+func (s ReadingSlice) WriteTo(w io.Writer) (int64, error) {
+	n := s.Len()
+	cSensor := s.Sensor[:n]
+	cTime := s.Time[:n]
+	cValue := s.Value[:n]
+	return soabin.Encode(w, n, soabin.Col("Sensor", cSensor), soabin.Col("Time", cTime), soabin.Col("Value", cValue))
+}
+
+// ReadFrom reads the SoA slice in the format of soabin.
+func (s *ReadingSlice) ReadFrom(r io.Reader) (int64, error) {
+	d := soabin.NewDecoder(r)
+	n, err := d.Len()
+	if err != nil {
+		return d.Count(), err
+	}
+	var (
+		cSensor []string
+		cTime   []int64
+		cValue  []float64
+	)
+	if err := d.Decode(soabin.Into("Sensor", &cSensor), soabin.Into("Time", &cTime), soabin.Into("Value", &cValue)); err != nil {
+		return d.Count(), err
+	}
+	*s = ReadingSlice{
+		Sensor: cSensor[:n:n],
+		Time:   cTime[:n:n],
+		Value:  cValue[:n:n],
+	}
+	return d.Count(), nil
+}
+
+// WriteArrow writes the SoA slice as a record batch in the Arrow IPC streaming format.
+func (s ReadingSlice) WriteArrow(w io.Writer) error {
+	n := s.Len()
+	cSensor := s.Sensor[:n]
+	cTime := s.Time[:n]
+	cValue := s.Value[:n]
+	return soaarrow.Write(w, n, soaarrow.Col("Sensor", cSensor), soaarrow.Col("Time", cTime), soaarrow.Col("Value", cValue))
+}
+
+// ReadArrow replaces the SoA slice with the rows of the record batches in the Arrow IPC streaming format.
+func (s *ReadingSlice) ReadArrow(r io.Reader) error {
+	d := soaarrow.NewReader(r)
+	t := *s
+	*s = ReadingSlice{}
+	for {
+		n, err := d.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			*s = t
+			return err
+		}
+		l := s.Len()
+		*s = s.Grow(n)
+		*s = s.Slice(0, l+n, s.Cap())
+		cSensor := s.Sensor[l : l+n]
+		cTime := s.Time[l : l+n]
+		cValue := s.Value[l : l+n]
+		if err := d.Decode(soaarrow.Col("Sensor", cSensor), soaarrow.Col("Time", cTime), soaarrow.Col("Value", cValue)); err != nil {
+			*s = t
+			return err
+		}
+	}
+}
@@ -0,0 +1,91 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"bytes"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ichiban/soa"
+	"github.com/ichiban/soa/soabin"
+	"github.com/ichiban/soa/soatest"
+)
+
+var _ soa.Slice[ReadingSlice, Reading] = ReadingSlice{}
+
+func TestReadingSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	t.Run("round trip", func(t *testing.T) {
+		s := soa.Make[ReadingSlice](3, 3)
+		for i := range s.Len() {
+			want := soatest.Value[Reading](r)
+			s.Set(i, want)
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.Sensor, want.Sensor) {
+				t.Errorf("Sensor: got %v, want %v", got.Sensor, want.Sensor)
+			}
+			if !reflect.DeepEqual(got.Time, want.Time) {
+				t.Errorf("Time: got %v, want %v", got.Time, want.Time)
+			}
+			if !reflect.DeepEqual(got.Value, want.Value) {
+				t.Errorf("Value: got %v, want %v", got.Value, want.Value)
+			}
+		}
+	})
+
+	t.Run("refs", func(t *testing.T) {
+		s := soa.Make[ReadingSlice](3, 3)
+		for i, ref := range s.Refs() {
+			want := soatest.Value[Reading](r)
+			*ref.Sensor = want.Sensor
+			*ref.Time = want.Time
+			*ref.Value = want.Value
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.Sensor, want.Sensor) {
+				t.Errorf("Sensor: got %v, want %v", got.Sensor, want.Sensor)
+			}
+			if !reflect.DeepEqual(got.Time, want.Time) {
+				t.Errorf("Time: got %v, want %v", got.Time, want.Time)
+			}
+			if !reflect.DeepEqual(got.Value, want.Value) {
+				t.Errorf("Value: got %v, want %v", got.Value, want.Value)
+			}
+		}
+	})
+
+	t.Run("binary", func(t *testing.T) {
+		for _, n := range []int{0, 5} {
+			want := make([]Reading, n)
+			for i := range want {
+				want[i] = soatest.Value[Reading](r)
+			}
+			b, err := soa.FromSlice[ReadingSlice](want).MarshalBinary()
+			if err != nil {
+				t.Fatal(err)
+			}
+			var s ReadingSlice
+			if err := s.UnmarshalBinary(b); err != nil {
+				t.Fatal(err)
+			}
+			if got := soa.ToSlice(s); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		}
+
+		// The header claims more rows than the columns have.
+		var w bytes.Buffer
+		if _, err := soabin.Encode(&w, 1<<30, soabin.Col("Sensor", []string{}), soabin.Col("Time", []int64{}), soabin.Col("Value", []float64{})); err != nil {
+			t.Fatal(err)
+		}
+		var s ReadingSlice
+		if err := s.UnmarshalBinary(w.Bytes()); err == nil {
+			t.Error("got no error for the broken number of rows")
+		}
+	})
+
+	soatest.Run[ReadingSlice](t, func() Reading {
+		return soatest.Value[Reading](r)
+	})
+}
@@ -0,0 +1,269 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+type PointSlice struct {
+	X     []float64
+	Y     []float64
+	Style []PointSliceStyle
+}
+
+type PointSliceStyle struct {
+	Label   string
+	Visible bool
+}
+
+type PointSliceRef struct {
+	X     *float64
+	Y     *float64
+	Style *PointSliceStyle
+}
+
+func (s PointSlice) Get(i int) Point {
+	var t Point
+	t.X = s.X[i]
+	t.Y = s.Y[i]
+	t.Label = s.Style[i].Label
+	t.Visible = s.Style[i].Visible
+	return t
+}
+
+func (s PointSlice) Set(i int, t Point) {
+	s.X[i] = t.X
+	s.Y[i] = t.Y
+	s.Style[i] = PointSliceStyle{
+		Label:   t.Label,
+		Visible: t.Visible,
+	}
+}
+
+func PointSliceFromSlice(es []Point) PointSlice {
+	var s PointSlice
+	s = s.Grow(len(es)).Slice(0, len(es), len(es))
+	for i := range es {
+		s.X[i] = es[i].X
+	}
+	for i := range es {
+		s.Y[i] = es[i].Y
+	}
+	for i := range es {
+		s.Style[i] = PointSliceStyle{
+			Label:   es[i].Label,
+			Visible: es[i].Visible,
+		}
+	}
+	return s
+}
+
+func (s PointSlice) ToSlice() []Point {
+	es := make([]Point, s.Len())
+	for i := range es {
+		es[i].X = s.X[i]
+	}
+	for i := range es {
+		es[i].Y = s.Y[i]
+	}
+	for i := range es {
+		c := s.Style[i]
+		es[i].Label = c.Label
+		es[i].Visible = c.Visible
+	}
+	return es
+}
+
+func (s PointSlice) Ref(i int) PointSliceRef {
+	return PointSliceRef{
+		X:     &s.X[i],
+		Y:     &s.Y[i],
+		Style: &s.Style[i],
+	}
+}
+
+func (s PointSlice) Refs() iter.Seq2[int, PointSliceRef] {
+	return func(yield func(int, PointSliceRef) bool) {
+		for i := 0; i < s.Len(); i++ {
+			if !yield(i, s.Ref(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (s PointSlice) GetX(i int) float64 {
+	return s.X[i]
+}
+
+func (s PointSlice) SetX(i int, v float64) {
+	s.X[i] = v
+}
+
+func (s PointSlice) XSeq() iter.Seq2[int, float64] {
+	return func(yield func(int, float64) bool) {
+		for i, v := range s.X[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s PointSlice) GetY(i int) float64 {
+	return s.Y[i]
+}
+
+func (s PointSlice) SetY(i int, v float64) {
+	s.Y[i] = v
+}
+
+func (s PointSlice) YSeq() iter.Seq2[int, float64] {
+	return func(yield func(int, float64) bool) {
+		for i, v := range s.Y[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s PointSlice) GetStyle(i int) PointSliceStyle {
+	return s.Style[i]
+}
+
+func (s PointSlice) SetStyle(i int, v PointSliceStyle) {
+	s.Style[i] = v
+}
+
+func (s PointSlice) StyleSeq() iter.Seq2[int, PointSliceStyle] {
+	return func(yield func(int, PointSliceStyle) bool) {
+		for i, v := range s.Style[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s PointSlice) Len() int {
+	return min(
+		len(s.X),
+		len(s.Y),
+		len(s.Style),
+	)
+}
+
+func (s PointSlice) Cap() int {
+	return min(
+		cap(s.X),
+		cap(s.Y),
+		cap(s.Style),
+	)
+}
+
+func (s PointSlice) Slice(low, high, max int) PointSlice {
+	return PointSlice{
+		X:     s.X[low:high:max],
+		Y:     s.Y[low:high:max],
+		Style: s.Style[low:high:max],
+	}
+}
+
+func (s PointSlice) Grow(n int) PointSlice {
+	return PointSlice{
+		X:     slices.Grow(s.X, n),
+		Y:     slices.Grow(s.Y, n),
+		Style: slices.Grow(s.Style, n),
+	}
+}
+
+func (s PointSlice) Columns() ([]float64, []float64, []PointSliceStyle) {
+	n := s.Len()
+	return s.X[:n], s.Y[:n], s.Style[:n]
+}
+
+func (s PointSlice) Swap(i, j int) {
+	s.X[i], s.X[j] = s.X[j], s.X[i]
+	s.Y[i], s.Y[j] = s.Y[j], s.Y[i]
+	s.Style[i], s.Style[j] = s.Style[j], s.Style[i]
+}
+
+func (s PointSlice) CopyWithin(dst, src, n int) {
+	copy(s.X[dst:dst+n], s.X[src:src+n])
+	copy(s.Y[dst:dst+n], s.Y[src:src+n])
+	copy(s.Style[dst:dst+n], s.Style[src:src+n])
+}
+
+// MarshalJSON encodes the SoA slice as an object of arrays. i.e. {"X":[1,2],"Y":[3,4]}
+func (s PointSlice) MarshalJSON() ([]byte, error) {
+	n := s.Len()
+	if n == 0 {
+		// Empty columns are encoded as empty arrays rather than null.
+		return []byte(`{"X":[],"Y":[],"Label":[],"Visible":[]}`), nil
+	}
+	cX := s.X[:n]
+	cY := s.Y[:n]
+	cLabel := make([]string, n)
+	for i := range n {
+		cLabel[i] = s.Style[i].Label
+	}
+	cVisible := make([]bool, n)
+	for i := range n {
+		cVisible[i] = s.Style[i].Visible
+	}
+	return json.Marshal(struct {
+		CX       []float64 `json:"X"`
+		CY       []float64 `json:"Y"`
+		CLabel   []string  `json:"Label"`
+		CVisible []bool    `json:"Visible"`
+	}{
+		CX:       cX,
+		CY:       cY,
+		CLabel:   cLabel,
+		CVisible: cVisible,
+	})
+}
+
+// UnmarshalJSON decodes the SoA slice from an object of arrays. All the arrays must have the same length.
+func (s *PointSlice) UnmarshalJSON(b []byte) error {
+	var v struct {
+		CX       []float64 `json:"X"`
+		CY       []float64 `json:"Y"`
+		CLabel   []string  `json:"Label"`
+		CVisible []bool    `json:"Visible"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	n := len(v.CX)
+	if len(v.CX) != n {
+		return fmt.Errorf("column X has %d elements, want %d", len(v.CX), n)
+	}
+	if len(v.CY) != n {
+		return fmt.Errorf("column Y has %d elements, want %d", len(v.CY), n)
+	}
+	if len(v.CLabel) != n {
+		return fmt.Errorf("column Label has %d elements, want %d", len(v.CLabel), n)
+	}
+	if len(v.CVisible) != n {
+		return fmt.Errorf("column Visible has %d elements, want %d", len(v.CVisible), n)
+	}
+	cX := v.CX
+	cY := v.CY
+	cLabel := v.CLabel
+	cVisible := v.CVisible
+	*s = PointSlice{
+		X:     cX[:n:n],
+		Y:     cY[:n:n],
+		Style: make([]PointSliceStyle, n),
+	}
+	for i := range n {
+		s.Style[i].Label = cLabel[i]
+		s.Style[i].Visible = cVisible[i]
+	}
+	return nil
+}
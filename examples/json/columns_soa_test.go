@@ -0,0 +1,92 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ichiban/soa"
+	"github.com/ichiban/soa/soatest"
+)
+
+var _ soa.Slice[PointSlice, Point] = PointSlice{}
+
+func TestPointSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	t.Run("round trip", func(t *testing.T) {
+		s := soa.Make[PointSlice](3, 3)
+		for i := range s.Len() {
+			want := soatest.Value[Point](r)
+			s.Set(i, want)
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.X, want.X) {
+				t.Errorf("X: got %v, want %v", got.X, want.X)
+			}
+			if !reflect.DeepEqual(got.Y, want.Y) {
+				t.Errorf("Y: got %v, want %v", got.Y, want.Y)
+			}
+			if !reflect.DeepEqual(got.Label, want.Label) {
+				t.Errorf("Label: got %v, want %v", got.Label, want.Label)
+			}
+			if !reflect.DeepEqual(got.Visible, want.Visible) {
+				t.Errorf("Visible: got %v, want %v", got.Visible, want.Visible)
+			}
+		}
+	})
+
+	t.Run("refs", func(t *testing.T) {
+		s := soa.Make[PointSlice](3, 3)
+		for i, ref := range s.Refs() {
+			want := soatest.Value[Point](r)
+			*ref.X = want.X
+			*ref.Y = want.Y
+			ref.Style.Label = want.Label
+			ref.Style.Visible = want.Visible
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.X, want.X) {
+				t.Errorf("X: got %v, want %v", got.X, want.X)
+			}
+			if !reflect.DeepEqual(got.Y, want.Y) {
+				t.Errorf("Y: got %v, want %v", got.Y, want.Y)
+			}
+			if !reflect.DeepEqual(got.Label, want.Label) {
+				t.Errorf("Label: got %v, want %v", got.Label, want.Label)
+			}
+			if !reflect.DeepEqual(got.Visible, want.Visible) {
+				t.Errorf("Visible: got %v, want %v", got.Visible, want.Visible)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		for _, n := range []int{0, 5} {
+			want := make([]Point, n)
+			for i := range want {
+				want[i] = soatest.Value[Point](r)
+			}
+			b, err := json.Marshal(soa.FromSlice[PointSlice](want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var s PointSlice
+			if err := json.Unmarshal(b, &s); err != nil {
+				t.Fatal(err)
+			}
+			if got := soa.ToSlice(s); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		}
+
+		var s PointSlice
+		if err := json.Unmarshal([]byte(`{"Y":[null]}`), &s); err == nil || err.Error() != "column Y has 1 elements, want 0" {
+			t.Errorf("got %v, want the error of the column length", err)
+		}
+	})
+
+	soatest.Run[PointSlice](t, func() Point {
+		return soatest.Value[Point](r)
+	})
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/ichiban/soa"
+)
+
+// Point is an example struct encoded as an object of arrays.
+type Point struct {
+	X, Y float64
+	// Label and Visible are stored in a column of a struct.
+	Label   string `soa:",group=Style"`
+	Visible bool   `soa:",group=Style"`
+}
+
+// Event is an example struct encoded as an array of objects.
+type Event struct {
+	ID   int64
+	Kind string
+	// Tags and OK are stored in a column of a struct.
+	Tags []string `soa:",group=Meta"`
+	OK   bool     `soa:",group=Meta"`
+}
+
+// To generate the SoA slices, run `go generate ./...`.
+// Point is encoded column by column while Event, stored in blocks of 4, is encoded row by row.
+//go:generate go run ../../cmd/soagen -test -json columns -out columns_soa.go Point
+//go:generate go run ../../cmd/soagen -test -json rows -block 4 -out rows_soa.go Event
+
+func main() {
+	ps := soa.FromSlice[PointSlice]([]Point{
+		{X: 1, Y: 2, Label: "a", Visible: true},
+		{X: 3, Y: 4, Label: "b"},
+	})
+	b, err := json.Marshal(ps)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+
+	es := soa.FromSlice[EventSlice]([]Event{
+		{ID: 1, Kind: "click", Tags: []string{"ui"}, OK: true},
+		{ID: 2, Kind: "scroll"},
+	})
+	b, err = json.Marshal(es)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+
+	var decoded EventSlice
+	if err := json.Unmarshal(b, &decoded); err != nil {
+		panic(err)
+	}
+	for i, e := range soa.All(decoded) {
+		fmt.Println(i, e)
+	}
+}
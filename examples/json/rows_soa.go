@@ -0,0 +1,322 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"iter"
+)
+
+type EventSlice struct {
+	ID   [][4]int64
+	Kind [][4]string
+	Meta [][4]EventSliceMeta
+
+	off, len, cap int
+}
+
+type EventSliceMeta struct {
+	Tags []string
+	OK   bool
+}
+
+type EventSliceRef struct {
+	ID   *int64
+	Kind *string
+	Meta *EventSliceMeta
+}
+
+func (s EventSlice) Get(i int) Event {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	var t Event
+	t.ID = s.ID[(s.off+i)/4][(s.off+i)%4]
+	t.Kind = s.Kind[(s.off+i)/4][(s.off+i)%4]
+	t.Tags = s.Meta[(s.off+i)/4][(s.off+i)%4].Tags
+	t.OK = s.Meta[(s.off+i)/4][(s.off+i)%4].OK
+	return t
+}
+
+func (s EventSlice) Set(i int, t Event) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.ID[(s.off+i)/4][(s.off+i)%4] = t.ID
+	s.Kind[(s.off+i)/4][(s.off+i)%4] = t.Kind
+	s.Meta[(s.off+i)/4][(s.off+i)%4] = EventSliceMeta{
+		Tags: t.Tags,
+		OK:   t.OK,
+	}
+}
+
+func EventSliceFromSlice(es []Event) EventSlice {
+	var s EventSlice
+	s = s.Grow(len(es)).Slice(0, len(es), len(es))
+	for i := range es {
+		s.ID[(s.off+i)/4][(s.off+i)%4] = es[i].ID
+	}
+	for i := range es {
+		s.Kind[(s.off+i)/4][(s.off+i)%4] = es[i].Kind
+	}
+	for i := range es {
+		s.Meta[(s.off+i)/4][(s.off+i)%4] = EventSliceMeta{
+			Tags: es[i].Tags,
+			OK:   es[i].OK,
+		}
+	}
+	return s
+}
+
+func (s EventSlice) ToSlice() []Event {
+	es := make([]Event, s.Len())
+	for i := range es {
+		es[i].ID = s.ID[(s.off+i)/4][(s.off+i)%4]
+	}
+	for i := range es {
+		es[i].Kind = s.Kind[(s.off+i)/4][(s.off+i)%4]
+	}
+	for i := range es {
+		c := s.Meta[(s.off+i)/4][(s.off+i)%4]
+		es[i].Tags = c.Tags
+		es[i].OK = c.OK
+	}
+	return es
+}
+
+func (s EventSlice) Ref(i int) EventSliceRef {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return EventSliceRef{
+		ID:   &s.ID[(s.off+i)/4][(s.off+i)%4],
+		Kind: &s.Kind[(s.off+i)/4][(s.off+i)%4],
+		Meta: &s.Meta[(s.off+i)/4][(s.off+i)%4],
+	}
+}
+
+func (s EventSlice) Refs() iter.Seq2[int, EventSliceRef] {
+	return func(yield func(int, EventSliceRef) bool) {
+		for i := 0; i < s.Len(); i++ {
+			if !yield(i, s.Ref(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (s EventSlice) GetID(i int) int64 {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return s.ID[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s EventSlice) SetID(i int, v int64) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.ID[(s.off+i)/4][(s.off+i)%4] = v
+}
+
+func (s EventSlice) IDSeq() iter.Seq2[int, int64] {
+	return func(yield func(int, int64) bool) {
+		for i := 0; i < s.len; i++ {
+			if !yield(i, s.ID[(s.off+i)/4][(s.off+i)%4]) {
+				return
+			}
+		}
+	}
+}
+
+func (s EventSlice) GetKind(i int) string {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return s.Kind[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s EventSlice) SetKind(i int, v string) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.Kind[(s.off+i)/4][(s.off+i)%4] = v
+}
+
+func (s EventSlice) KindSeq() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i := 0; i < s.len; i++ {
+			if !yield(i, s.Kind[(s.off+i)/4][(s.off+i)%4]) {
+				return
+			}
+		}
+	}
+}
+
+func (s EventSlice) GetMeta(i int) EventSliceMeta {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return s.Meta[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s EventSlice) SetMeta(i int, v EventSliceMeta) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.Meta[(s.off+i)/4][(s.off+i)%4] = v
+}
+
+func (s EventSlice) MetaSeq() iter.Seq2[int, EventSliceMeta] {
+	return func(yield func(int, EventSliceMeta) bool) {
+		for i := 0; i < s.len; i++ {
+			if !yield(i, s.Meta[(s.off+i)/4][(s.off+i)%4]) {
+				return
+			}
+		}
+	}
+}
+
+func (s EventSlice) Len() int {
+	return s.len
+}
+
+func (s EventSlice) Cap() int {
+	return s.cap
+}
+
+func (s EventSlice) Slice(low, high, max int) EventSlice {
+	if low < 0 || high < low || max < high || s.cap < max {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d:%d] with capacity %d", low, high, max, s.cap))
+	}
+	off := s.off + low
+	b, e := off/4, (s.off+max+4-1)/4
+	return EventSlice{
+		ID:   s.ID[b:e:e],
+		Kind: s.Kind[b:e:e],
+		Meta: s.Meta[b:e:e],
+		off:  off % 4,
+		len:  high - low,
+		cap:  max - low,
+	}
+}
+
+func (s EventSlice) Grow(n int) EventSlice {
+	if n < 0 {
+		panic("cannot be negative")
+	}
+	if s.len+n <= s.cap {
+		return s
+	}
+	m := max((s.off+s.len+n+4-1)/4, 2*len(s.ID))
+	t := EventSlice{
+		ID:   make([][4]int64, m),
+		Kind: make([][4]string, m),
+		Meta: make([][4]EventSliceMeta, m),
+		off:  s.off,
+		len:  s.len,
+		cap:  m*4 - s.off,
+	}
+	copy(t.ID, s.ID)
+	copy(t.Kind, s.Kind)
+	copy(t.Meta, s.Meta)
+	return t
+}
+
+func (s EventSlice) Swap(i, j int) {
+	for _, k := range [...]int{i, j} {
+		if uint(k) >= uint(s.len) {
+			panic(fmt.Sprintf("index out of range [%d] with length %d", k, s.len))
+		}
+	}
+	s.ID[(s.off+i)/4][(s.off+i)%4], s.ID[(s.off+j)/4][(s.off+j)%4] = s.ID[(s.off+j)/4][(s.off+j)%4], s.ID[(s.off+i)/4][(s.off+i)%4]
+	s.Kind[(s.off+i)/4][(s.off+i)%4], s.Kind[(s.off+j)/4][(s.off+j)%4] = s.Kind[(s.off+j)/4][(s.off+j)%4], s.Kind[(s.off+i)/4][(s.off+i)%4]
+	s.Meta[(s.off+i)/4][(s.off+i)%4], s.Meta[(s.off+j)/4][(s.off+j)%4] = s.Meta[(s.off+j)/4][(s.off+j)%4], s.Meta[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s EventSlice) CopyWithin(dst, src, n int) {
+	if dst < 0 || src < 0 || n < 0 || s.cap-n < dst || s.cap-n < src {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d] or [%d:%d] with capacity %d", dst, dst+n, src, src+n, s.cap))
+	}
+	if dst < src {
+		for k := 0; k < n; k++ {
+			s.ID[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.ID[(s.off+src+k)/4][(s.off+src+k)%4]
+			s.Kind[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.Kind[(s.off+src+k)/4][(s.off+src+k)%4]
+			s.Meta[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.Meta[(s.off+src+k)/4][(s.off+src+k)%4]
+		}
+		return
+	}
+	for k := n - 1; k >= 0; k-- {
+		s.ID[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.ID[(s.off+src+k)/4][(s.off+src+k)%4]
+		s.Kind[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.Kind[(s.off+src+k)/4][(s.off+src+k)%4]
+		s.Meta[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.Meta[(s.off+src+k)/4][(s.off+src+k)%4]
+	}
+}
+
+// MarshalJSON encodes the SoA slice as an array of objects. i.e. [{"X":1,"Y":3},{"X":2,"Y":4}]
+func (s EventSlice) MarshalJSON() ([]byte, error) {
+	type row struct {
+		CID   int64    `json:"ID"`
+		CKind string   `json:"Kind"`
+		CTags []string `json:"Tags"`
+		COK   bool     `json:"OK"`
+	}
+	rs := make([]row, s.Len())
+	for i := range rs {
+		rs[i] = row{
+			CID:   s.ID[(s.off+i)/4][(s.off+i)%4],
+			CKind: s.Kind[(s.off+i)/4][(s.off+i)%4],
+			CTags: s.Meta[(s.off+i)/4][(s.off+i)%4].Tags,
+			COK:   s.Meta[(s.off+i)/4][(s.off+i)%4].OK,
+		}
+	}
+	return json.Marshal(rs)
+}
+
+// UnmarshalJSON decodes the SoA slice from an array of objects. Every object must have all the columns.
+func (s *EventSlice) UnmarshalJSON(b []byte) error {
+	var rs []map[string]json.RawMessage
+	if err := json.Unmarshal(b, &rs); err != nil {
+		return err
+	}
+	n := len(rs)
+	cID := make([]int64, n)
+	cKind := make([]string, n)
+	cTags := make([][]string, n)
+	cOK := make([]bool, n)
+	for i, r := range rs {
+		if c, ok := r["ID"]; !ok {
+			return fmt.Errorf("row %d has no column ID", i)
+		} else if err := json.Unmarshal(c, &cID[i]); err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+		if c, ok := r["Kind"]; !ok {
+			return fmt.Errorf("row %d has no column Kind", i)
+		} else if err := json.Unmarshal(c, &cKind[i]); err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+		if c, ok := r["Tags"]; !ok {
+			return fmt.Errorf("row %d has no column Tags", i)
+		} else if err := json.Unmarshal(c, &cTags[i]); err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+		if c, ok := r["OK"]; !ok {
+			return fmt.Errorf("row %d has no column OK", i)
+		} else if err := json.Unmarshal(c, &cOK[i]); err != nil {
+			return fmt.Errorf("row %d: %w", i, err)
+		}
+	}
+	var t EventSlice
+	*s = t.Grow(n).Slice(0, n, n)
+	for i := range n {
+		s.ID[(s.off+i)/4][(s.off+i)%4] = cID[i]
+	}
+	for i := range n {
+		s.Kind[(s.off+i)/4][(s.off+i)%4] = cKind[i]
+	}
+	for i := range n {
+		s.Meta[(s.off+i)/4][(s.off+i)%4].Tags = cTags[i]
+		s.Meta[(s.off+i)/4][(s.off+i)%4].OK = cOK[i]
+	}
+	return nil
+}
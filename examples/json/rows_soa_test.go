@@ -0,0 +1,92 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ichiban/soa"
+	"github.com/ichiban/soa/soatest"
+)
+
+var _ soa.Slice[EventSlice, Event] = EventSlice{}
+
+func TestEventSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	t.Run("round trip", func(t *testing.T) {
+		s := soa.Make[EventSlice](3, 3)
+		for i := range s.Len() {
+			want := soatest.Value[Event](r)
+			s.Set(i, want)
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.ID, want.ID) {
+				t.Errorf("ID: got %v, want %v", got.ID, want.ID)
+			}
+			if !reflect.DeepEqual(got.Kind, want.Kind) {
+				t.Errorf("Kind: got %v, want %v", got.Kind, want.Kind)
+			}
+			if !reflect.DeepEqual(got.Tags, want.Tags) {
+				t.Errorf("Tags: got %v, want %v", got.Tags, want.Tags)
+			}
+			if !reflect.DeepEqual(got.OK, want.OK) {
+				t.Errorf("OK: got %v, want %v", got.OK, want.OK)
+			}
+		}
+	})
+
+	t.Run("refs", func(t *testing.T) {
+		s := soa.Make[EventSlice](3, 3)
+		for i, ref := range s.Refs() {
+			want := soatest.Value[Event](r)
+			*ref.ID = want.ID
+			*ref.Kind = want.Kind
+			ref.Meta.Tags = want.Tags
+			ref.Meta.OK = want.OK
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.ID, want.ID) {
+				t.Errorf("ID: got %v, want %v", got.ID, want.ID)
+			}
+			if !reflect.DeepEqual(got.Kind, want.Kind) {
+				t.Errorf("Kind: got %v, want %v", got.Kind, want.Kind)
+			}
+			if !reflect.DeepEqual(got.Tags, want.Tags) {
+				t.Errorf("Tags: got %v, want %v", got.Tags, want.Tags)
+			}
+			if !reflect.DeepEqual(got.OK, want.OK) {
+				t.Errorf("OK: got %v, want %v", got.OK, want.OK)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		for _, n := range []int{0, 5} {
+			want := make([]Event, n)
+			for i := range want {
+				want[i] = soatest.Value[Event](r)
+			}
+			b, err := json.Marshal(soa.FromSlice[EventSlice](want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var s EventSlice
+			if err := json.Unmarshal(b, &s); err != nil {
+				t.Fatal(err)
+			}
+			if got := soa.ToSlice(s); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		}
+
+		var s EventSlice
+		if err := json.Unmarshal([]byte(`[{}]`), &s); err == nil || err.Error() != "row 0 has no column ID" {
+			t.Errorf("got %v, want the error of the missing column", err)
+		}
+	})
+
+	soatest.Run[EventSlice](t, func() Event {
+		return soatest.Value[Event](r)
+	})
+}
@@ -0,0 +1,99 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+)
+
+func init() {
+	sql.Register("fake", fakeDriver{tables: map[string]fakeTable{
+		"orders": {
+			columns: []string{"id", "customer_name", "note", "total", "Paid"},
+			rows: [][]driver.Value{
+				{int64(1), "Alice", nil, "12.34", true},
+				{int64(2), "Bob", "gift", int64(5), false},
+				{int64(3), "Charlie", nil, []byte("0.5"), true},
+			},
+		},
+	}})
+}
+
+// fakeDriver is an in-memory database/sql driver which returns the rows of the table named by the query so that this
+// example runs without a database.
+type fakeDriver struct {
+	tables map[string]fakeTable
+}
+
+type fakeTable struct {
+	columns []string
+	rows    [][]driver.Value
+}
+
+func (d fakeDriver) Open(string) (driver.Conn, error) {
+	return fakeConn{tables: d.tables}, nil
+}
+
+type fakeConn struct {
+	tables map[string]fakeTable
+}
+
+func (c fakeConn) Prepare(query string) (driver.Stmt, error) {
+	t, ok := c.tables[query]
+	if !ok {
+		return nil, fmt.Errorf("no table %s", query)
+	}
+	return fakeStmt{table: t}, nil
+}
+
+func (fakeConn) Close() error {
+	return nil
+}
+
+func (fakeConn) Begin() (driver.Tx, error) {
+	return nil, errors.New("transactions are not supported")
+}
+
+type fakeStmt struct {
+	table fakeTable
+}
+
+func (fakeStmt) Close() error {
+	return nil
+}
+
+func (fakeStmt) NumInput() int {
+	return 0
+}
+
+func (fakeStmt) Exec([]driver.Value) (driver.Result, error) {
+	return nil, errors.New("exec is not supported")
+}
+
+func (s fakeStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &fakeRows{table: s.table}, nil
+}
+
+type fakeRows struct {
+	table fakeTable
+	next  int
+}
+
+func (r *fakeRows) Columns() []string {
+	return r.table.columns
+}
+
+func (r *fakeRows) Close() error {
+	return nil
+}
+
+func (r *fakeRows) Next(dest []driver.Value) error {
+	if r.next >= len(r.table.rows) {
+		return io.EOF
+	}
+	copy(dest, r.table.rows[r.next])
+	r.next++
+	return nil
+}
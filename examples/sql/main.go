@@ -0,0 +1,83 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/ichiban/soa"
+)
+
+// Order is an example struct scanned from database/sql. The `db` tags map the columns of the rows to the fields and
+// the untagged fields are mapped by their names.
+type Order struct {
+	ID       int64          `db:"id"`
+	Customer string         `db:"customer_name"`
+	Note     sql.NullString `db:"note"`
+	Total    Cents          `db:"total"`
+	Paid     bool
+}
+
+// Cents is an amount of money which implements sql.Scanner to read a decimal such as "12.34".
+type Cents int64
+
+func (c *Cents) Scan(src any) error {
+	var s string
+	switch src := src.(type) {
+	case int64:
+		*c = Cents(100 * src)
+		return nil
+	case string:
+		s = src
+	case []byte:
+		s = string(src)
+	default:
+		return fmt.Errorf("cannot scan %T into Cents", src)
+	}
+	units, frac, _ := strings.Cut(s, ".")
+	u, err := strconv.ParseInt(units, 10, 64)
+	if err != nil {
+		return err
+	}
+	f, err := strconv.ParseInt((frac + "00")[:2], 10, 64)
+	if err != nil {
+		return err
+	}
+	*c = Cents(100*u + f)
+	return nil
+}
+
+func (c Cents) String() string {
+	return fmt.Sprintf("%d.%02d", c/100, c%100)
+}
+
+// To generate an SoA slice with ScanRows, run `go generate ./...`.
+//go:generate go run ../../cmd/soagen -test -sql
+
+func main() {
+	// The fake driver stands in for a real one such as PostgreSQL or SQLite.
+	db, err := sql.Open("fake", "")
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("orders")
+	if err != nil {
+		panic(err)
+	}
+	defer rows.Close()
+
+	// Pre-size the SoA slice if you know the number of the rows.
+	s := soa.Make[OrderSlice](0, 3)
+
+	// ScanRows appends the rows column by column.
+	if err := s.ScanRows(rows); err != nil {
+		panic(err)
+	}
+
+	for i, o := range soa.All(s) {
+		fmt.Println(i, o.ID, o.Customer, o.Note.String, o.Total, o.Paid)
+	}
+}
@@ -0,0 +1,299 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"iter"
+	"slices"
+)
+
+type OrderSlice struct {
+	ID       []int64
+	Customer []string
+	Note     []sql.NullString
+	Total    []Cents
+	Paid     []bool
+}
+
+type OrderSliceRef struct {
+	ID       *int64
+	Customer *string
+	Note     *sql.NullString
+	Total    *Cents
+	Paid     *bool
+}
+
+func (s OrderSlice) Get(i int) Order {
+	var t Order
+	t.ID = s.ID[i]
+	t.Customer = s.Customer[i]
+	t.Note = s.Note[i]
+	t.Total = s.Total[i]
+	t.Paid = s.Paid[i]
+	return t
+}
+
+func (s OrderSlice) Set(i int, t Order) {
+	s.ID[i] = t.ID
+	s.Customer[i] = t.Customer
+	s.Note[i] = t.Note
+	s.Total[i] = t.Total
+	s.Paid[i] = t.Paid
+}
+
+func OrderSliceFromSlice(es []Order) OrderSlice {
+	var s OrderSlice
+	s = s.Grow(len(es)).Slice(0, len(es), len(es))
+	for i := range es {
+		s.ID[i] = es[i].ID
+	}
+	for i := range es {
+		s.Customer[i] = es[i].Customer
+	}
+	for i := range es {
+		s.Note[i] = es[i].Note
+	}
+	for i := range es {
+		s.Total[i] = es[i].Total
+	}
+	for i := range es {
+		s.Paid[i] = es[i].Paid
+	}
+	return s
+}
+
+func (s OrderSlice) ToSlice() []Order {
+	es := make([]Order, s.Len())
+	for i := range es {
+		es[i].ID = s.ID[i]
+	}
+	for i := range es {
+		es[i].Customer = s.Customer[i]
+	}
+	for i := range es {
+		es[i].Note = s.Note[i]
+	}
+	for i := range es {
+		es[i].Total = s.Total[i]
+	}
+	for i := range es {
+		es[i].Paid = s.Paid[i]
+	}
+	return es
+}
+
+func (s OrderSlice) Ref(i int) OrderSliceRef {
+	return OrderSliceRef{
+		ID:       &s.ID[i],
+		Customer: &s.Customer[i],
+		Note:     &s.Note[i],
+		Total:    &s.Total[i],
+		Paid:     &s.Paid[i],
+	}
+}
+
+func (s OrderSlice) Refs() iter.Seq2[int, OrderSliceRef] {
+	return func(yield func(int, OrderSliceRef) bool) {
+		for i := 0; i < s.Len(); i++ {
+			if !yield(i, s.Ref(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (s OrderSlice) GetID(i int) int64 {
+	return s.ID[i]
+}
+
+func (s OrderSlice) SetID(i int, v int64) {
+	s.ID[i] = v
+}
+
+func (s OrderSlice) IDSeq() iter.Seq2[int, int64] {
+	return func(yield func(int, int64) bool) {
+		for i, v := range s.ID[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s OrderSlice) GetCustomer(i int) string {
+	return s.Customer[i]
+}
+
+func (s OrderSlice) SetCustomer(i int, v string) {
+	s.Customer[i] = v
+}
+
+func (s OrderSlice) CustomerSeq() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i, v := range s.Customer[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s OrderSlice) GetNote(i int) sql.NullString {
+	return s.Note[i]
+}
+
+func (s OrderSlice) SetNote(i int, v sql.NullString) {
+	s.Note[i] = v
+}
+
+func (s OrderSlice) NoteSeq() iter.Seq2[int, sql.NullString] {
+	return func(yield func(int, sql.NullString) bool) {
+		for i, v := range s.Note[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s OrderSlice) GetTotal(i int) Cents {
+	return s.Total[i]
+}
+
+func (s OrderSlice) SetTotal(i int, v Cents) {
+	s.Total[i] = v
+}
+
+func (s OrderSlice) TotalSeq() iter.Seq2[int, Cents] {
+	return func(yield func(int, Cents) bool) {
+		for i, v := range s.Total[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s OrderSlice) GetPaid(i int) bool {
+	return s.Paid[i]
+}
+
+func (s OrderSlice) SetPaid(i int, v bool) {
+	s.Paid[i] = v
+}
+
+func (s OrderSlice) PaidSeq() iter.Seq2[int, bool] {
+	return func(yield func(int, bool) bool) {
+		for i, v := range s.Paid[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s OrderSlice) Len() int {
+	return min(
+		len(s.ID),
+		len(s.Customer),
+		len(s.Note),
+		len(s.Total),
+		len(s.Paid),
+	)
+}
+
+func (s OrderSlice) Cap() int {
+	return min(
+		cap(s.ID),
+		cap(s.Customer),
+		cap(s.Note),
+		cap(s.Total),
+		cap(s.Paid),
+	)
+}
+
+func (s OrderSlice) Slice(low, high, max int) OrderSlice {
+	return OrderSlice{
+		ID:       s.ID[low:high:max],
+		Customer: s.Customer[low:high:max],
+		Note:     s.Note[low:high:max],
+		Total:    s.Total[low:high:max],
+		Paid:     s.Paid[low:high:max],
+	}
+}
+
+func (s OrderSlice) Grow(n int) OrderSlice {
+	return OrderSlice{
+		ID:       slices.Grow(s.ID, n),
+		Customer: slices.Grow(s.Customer, n),
+		Note:     slices.Grow(s.Note, n),
+		Total:    slices.Grow(s.Total, n),
+		Paid:     slices.Grow(s.Paid, n),
+	}
+}
+
+func (s OrderSlice) Columns() ([]int64, []string, []sql.NullString, []Cents, []bool) {
+	n := s.Len()
+	return s.ID[:n], s.Customer[:n], s.Note[:n], s.Total[:n], s.Paid[:n]
+}
+
+func (s OrderSlice) Swap(i, j int) {
+	s.ID[i], s.ID[j] = s.ID[j], s.ID[i]
+	s.Customer[i], s.Customer[j] = s.Customer[j], s.Customer[i]
+	s.Note[i], s.Note[j] = s.Note[j], s.Note[i]
+	s.Total[i], s.Total[j] = s.Total[j], s.Total[i]
+	s.Paid[i], s.Paid[j] = s.Paid[j], s.Paid[i]
+}
+
+func (s OrderSlice) CopyWithin(dst, src, n int) {
+	copy(s.ID[dst:dst+n], s.ID[src:src+n])
+	copy(s.Customer[dst:dst+n], s.Customer[src:src+n])
+	copy(s.Note[dst:dst+n], s.Note[src:src+n])
+	copy(s.Total[dst:dst+n], s.Total[src:src+n])
+	copy(s.Paid[dst:dst+n], s.Paid[src:src+n])
+}
+
+// ScanRows appends the rows to the SoA slice. The columns of the rows are mapped to the fields by the names in the `db`
+// struct tags or the names of the columns of the SoA slice. Grow the SoA slice beforehand if you know the number of the
+// rows.
+func (s *OrderSlice) ScanRows(rows *sql.Rows) error {
+	cols, err := rows.Columns()
+	if err != nil {
+		return err
+	}
+	ptrs := make([]func(i int) any, len(cols))
+	for j, c := range cols {
+		switch c {
+		case "id":
+			ptrs[j] = func(i int) any { return &s.ID[i] }
+		case "customer_name":
+			ptrs[j] = func(i int) any { return &s.Customer[i] }
+		case "note":
+			ptrs[j] = func(i int) any { return &s.Note[i] }
+		case "total":
+			ptrs[j] = func(i int) any { return &s.Total[i] }
+		case "Paid":
+			ptrs[j] = func(i int) any { return &s.Paid[i] }
+		default:
+			return fmt.Errorf("no field for column %s", c)
+		}
+	}
+	var zero Order
+	dest := make([]any, len(cols))
+	for rows.Next() {
+		i := s.Len()
+		*s = s.Grow(1)
+		*s = s.Slice(0, i+1, s.Cap())
+		// The element may be left in the capacity, so the fields which are not in the rows have to be reset.
+		s.Set(i, zero)
+		for j, p := range ptrs {
+			dest[j] = p(i)
+		}
+		if err := rows.Scan(dest...); err != nil {
+			*s = s.Slice(0, i, s.Cap())
+			return err
+		}
+	}
+	return rows.Err()
+}
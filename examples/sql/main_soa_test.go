@@ -0,0 +1,73 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ichiban/soa"
+	"github.com/ichiban/soa/soatest"
+)
+
+var _ soa.Slice[OrderSlice, Order] = OrderSlice{}
+
+func TestOrderSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	t.Run("round trip", func(t *testing.T) {
+		s := soa.Make[OrderSlice](3, 3)
+		for i := range s.Len() {
+			want := soatest.Value[Order](r)
+			s.Set(i, want)
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.ID, want.ID) {
+				t.Errorf("ID: got %v, want %v", got.ID, want.ID)
+			}
+			if !reflect.DeepEqual(got.Customer, want.Customer) {
+				t.Errorf("Customer: got %v, want %v", got.Customer, want.Customer)
+			}
+			if !reflect.DeepEqual(got.Note, want.Note) {
+				t.Errorf("Note: got %v, want %v", got.Note, want.Note)
+			}
+			if !reflect.DeepEqual(got.Total, want.Total) {
+				t.Errorf("Total: got %v, want %v", got.Total, want.Total)
+			}
+			if !reflect.DeepEqual(got.Paid, want.Paid) {
+				t.Errorf("Paid: got %v, want %v", got.Paid, want.Paid)
+			}
+		}
+	})
+
+	t.Run("refs", func(t *testing.T) {
+		s := soa.Make[OrderSlice](3, 3)
+		for i, ref := range s.Refs() {
+			want := soatest.Value[Order](r)
+			*ref.ID = want.ID
+			*ref.Customer = want.Customer
+			*ref.Note = want.Note
+			*ref.Total = want.Total
+			*ref.Paid = want.Paid
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.ID, want.ID) {
+				t.Errorf("ID: got %v, want %v", got.ID, want.ID)
+			}
+			if !reflect.DeepEqual(got.Customer, want.Customer) {
+				t.Errorf("Customer: got %v, want %v", got.Customer, want.Customer)
+			}
+			if !reflect.DeepEqual(got.Note, want.Note) {
+				t.Errorf("Note: got %v, want %v", got.Note, want.Note)
+			}
+			if !reflect.DeepEqual(got.Total, want.Total) {
+				t.Errorf("Total: got %v, want %v", got.Total, want.Total)
+			}
+			if !reflect.DeepEqual(got.Paid, want.Paid) {
+				t.Errorf("Paid: got %v, want %v", got.Paid, want.Paid)
+			}
+		}
+	})
+
+	soatest.Run[OrderSlice](t, func() Order {
+		return soatest.Value[Order](r)
+	})
+}
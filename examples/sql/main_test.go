@@ -0,0 +1,138 @@
+package main
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"testing"
+
+	"github.com/ichiban/soa"
+)
+
+func init() {
+	sql.Register("fake_test", fakeDriver{tables: map[string]fakeTable{
+		"orders": {
+			columns: []string{"total", "id", "note", "Paid"},
+			rows: [][]driver.Value{
+				{"1.5", int64(4), "rush", true},
+				{int64(2), int64(5), nil, false},
+			},
+		},
+		"unknown column": {
+			columns: []string{"id", "Paid", "discount"},
+			rows:    [][]driver.Value{{int64(6), true, int64(1)}},
+		},
+		"ids": {
+			columns: []string{"id"},
+			rows:    [][]driver.Value{{int64(9)}},
+		},
+		"invalid total": {
+			columns: []string{"id", "total"},
+			rows: [][]driver.Value{
+				{int64(7), "3.00"},
+				{int64(8), "free"},
+			},
+		},
+	}})
+}
+
+func TestOrderSlice_ScanRows(t *testing.T) {
+	existing := []Order{{ID: 1, Customer: "Alice", Total: 100}}
+	tests := []struct {
+		title string
+		query string
+		want  []Order
+		err   string
+	}{
+		{
+			title: "by names and db tags",
+			query: "orders",
+			want: append(existing,
+				Order{ID: 4, Note: sql.NullString{String: "rush", Valid: true}, Total: 150, Paid: true},
+				Order{ID: 5, Total: 200},
+			),
+		},
+		{title: "unknown column", query: "unknown column", want: existing, err: "no field for column discount"},
+		{
+			title: "scan error",
+			query: "invalid total",
+			want:  append(existing, Order{ID: 7, Total: 300}),
+			err:   `sql: Scan error on column index 1, name "total": strconv.ParseInt: parsing "free": invalid syntax`,
+		},
+	}
+
+	db, err := sql.Open("fake_test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			rows, err := db.Query(test.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer rows.Close()
+
+			s := soa.FromSlice[OrderSlice](existing)
+			err = s.ScanRows(rows)
+			if test.err == "" && err != nil || test.err != "" && (err == nil || err.Error() != test.err) {
+				t.Errorf("got %v, want %q", err, test.err)
+			}
+			if got := soa.ToSlice(s); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestOrderSlice_ScanRows_grow(t *testing.T) {
+	db, err := sql.Open("fake", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	rows, err := db.Query("orders")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	s := soa.Make[OrderSlice](0, 3)
+	id := &s.ID[:1][0]
+	if err := s.ScanRows(rows); err != nil {
+		t.Fatal(err)
+	}
+	if s.Len() != 3 || s.Cap() != 3 {
+		t.Errorf("got len %d cap %d, want 3 and 3", s.Len(), s.Cap())
+	}
+	if &s.ID[0] != id {
+		t.Error("got reallocated columns")
+	}
+}
+
+func TestOrderSlice_ScanRows_reuse(t *testing.T) {
+	db, err := sql.Open("fake_test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	s := soa.FromSlice[OrderSlice]([]Order{{ID: 1, Customer: "Alice", Note: sql.NullString{String: "gift", Valid: true}, Total: 100, Paid: true}})
+	s = s.Slice(0, 0, s.Cap())
+
+	rows, err := db.Query("ids")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	if err := s.ScanRows(rows); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := soa.ToSlice(s), []Order{{ID: 9}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("got %+v, want %+v", got, want)
+	}
+}
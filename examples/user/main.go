@@ -1,6 +1,7 @@
 package main
 
 import (
+	"encoding/json"
 	"fmt"
 
 	"github.com/ichiban/soa"
@@ -18,7 +19,9 @@ type User struct {
 
 // To generate an SoA slice, run `go generate ./...`.
 // It'll generate UserSlice in *_soa.go from the declaration of User in this file.
-//go:generate go run ../../cmd/soagen
+//go:generate go run ../../cmd/soagen -test -json columns
+// With `-block`, it'll also generate UserBlocks which stores Users in blocks of 4 in each column.
+//go:generate go run ../../cmd/soagen -test -block 4 -name {{.}}Blocks -out main_block_soa.go User
 
 func main() {
 	// Now you can use UserSlice to store User.
@@ -36,4 +39,20 @@ func main() {
 	for i, u := range soa.All(s) {
 		fmt.Println(i, u)
 	}
+
+	// With `-json columns`, UserSlice is encoded column by column including the unexported ones.
+	b, err := json.Marshal(s)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(string(b))
+
+	// UserBlocks is used in the same way as UserSlice while the columns are slices of arrays of 4 Users' fields.
+	bs := soa.FromSlice[UserBlocks](soa.ToSlice(s))
+	bs = soa.DeleteFunc(bs, func(u User) bool {
+		return u.deleted
+	})
+	for i, u := range soa.All(bs) {
+		fmt.Println(i, u)
+	}
 }
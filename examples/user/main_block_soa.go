@@ -0,0 +1,215 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"fmt"
+	"iter"
+)
+
+type UserBlocks struct {
+	ID      [][4]int
+	Name    [][4]string
+	deleted [][4]bool
+
+	off, len, cap int
+}
+
+type UserBlocksRef struct {
+	ID      *int
+	Name    *string
+	deleted *bool
+}
+
+func (s UserBlocks) Get(i int) User {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	var t User
+	t.ID = s.ID[(s.off+i)/4][(s.off+i)%4]
+	t.Name = s.Name[(s.off+i)/4][(s.off+i)%4]
+	t.deleted = s.deleted[(s.off+i)/4][(s.off+i)%4]
+	return t
+}
+
+func (s UserBlocks) Set(i int, t User) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.ID[(s.off+i)/4][(s.off+i)%4] = t.ID
+	s.Name[(s.off+i)/4][(s.off+i)%4] = t.Name
+	s.deleted[(s.off+i)/4][(s.off+i)%4] = t.deleted
+}
+
+func UserBlocksFromSlice(es []User) UserBlocks {
+	var s UserBlocks
+	s = s.Grow(len(es)).Slice(0, len(es), len(es))
+	for i := range es {
+		s.ID[(s.off+i)/4][(s.off+i)%4] = es[i].ID
+	}
+	for i := range es {
+		s.Name[(s.off+i)/4][(s.off+i)%4] = es[i].Name
+	}
+	for i := range es {
+		s.deleted[(s.off+i)/4][(s.off+i)%4] = es[i].deleted
+	}
+	return s
+}
+
+func (s UserBlocks) ToSlice() []User {
+	es := make([]User, s.Len())
+	for i := range es {
+		es[i].ID = s.ID[(s.off+i)/4][(s.off+i)%4]
+	}
+	for i := range es {
+		es[i].Name = s.Name[(s.off+i)/4][(s.off+i)%4]
+	}
+	for i := range es {
+		es[i].deleted = s.deleted[(s.off+i)/4][(s.off+i)%4]
+	}
+	return es
+}
+
+func (s UserBlocks) Ref(i int) UserBlocksRef {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return UserBlocksRef{
+		ID:      &s.ID[(s.off+i)/4][(s.off+i)%4],
+		Name:    &s.Name[(s.off+i)/4][(s.off+i)%4],
+		deleted: &s.deleted[(s.off+i)/4][(s.off+i)%4],
+	}
+}
+
+func (s UserBlocks) Refs() iter.Seq2[int, UserBlocksRef] {
+	return func(yield func(int, UserBlocksRef) bool) {
+		for i := 0; i < s.Len(); i++ {
+			if !yield(i, s.Ref(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (s UserBlocks) GetID(i int) int {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return s.ID[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s UserBlocks) SetID(i int, v int) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.ID[(s.off+i)/4][(s.off+i)%4] = v
+}
+
+func (s UserBlocks) IDSeq() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for i := 0; i < s.len; i++ {
+			if !yield(i, s.ID[(s.off+i)/4][(s.off+i)%4]) {
+				return
+			}
+		}
+	}
+}
+
+func (s UserBlocks) GetName(i int) string {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	return s.Name[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s UserBlocks) SetName(i int, v string) {
+	if uint(i) >= uint(s.len) {
+		panic(fmt.Sprintf("index out of range [%d] with length %d", i, s.len))
+	}
+	s.Name[(s.off+i)/4][(s.off+i)%4] = v
+}
+
+func (s UserBlocks) NameSeq() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i := 0; i < s.len; i++ {
+			if !yield(i, s.Name[(s.off+i)/4][(s.off+i)%4]) {
+				return
+			}
+		}
+	}
+}
+
+func (s UserBlocks) Len() int {
+	return s.len
+}
+
+func (s UserBlocks) Cap() int {
+	return s.cap
+}
+
+func (s UserBlocks) Slice(low, high, max int) UserBlocks {
+	if low < 0 || high < low || max < high || s.cap < max {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d:%d] with capacity %d", low, high, max, s.cap))
+	}
+	off := s.off + low
+	b, e := off/4, (s.off+max+4-1)/4
+	return UserBlocks{
+		ID:      s.ID[b:e:e],
+		Name:    s.Name[b:e:e],
+		deleted: s.deleted[b:e:e],
+		off:     off % 4,
+		len:     high - low,
+		cap:     max - low,
+	}
+}
+
+func (s UserBlocks) Grow(n int) UserBlocks {
+	if n < 0 {
+		panic("cannot be negative")
+	}
+	if s.len+n <= s.cap {
+		return s
+	}
+	m := max((s.off+s.len+n+4-1)/4, 2*len(s.ID))
+	t := UserBlocks{
+		ID:      make([][4]int, m),
+		Name:    make([][4]string, m),
+		deleted: make([][4]bool, m),
+		off:     s.off,
+		len:     s.len,
+		cap:     m*4 - s.off,
+	}
+	copy(t.ID, s.ID)
+	copy(t.Name, s.Name)
+	copy(t.deleted, s.deleted)
+	return t
+}
+
+func (s UserBlocks) Swap(i, j int) {
+	for _, k := range [...]int{i, j} {
+		if uint(k) >= uint(s.len) {
+			panic(fmt.Sprintf("index out of range [%d] with length %d", k, s.len))
+		}
+	}
+	s.ID[(s.off+i)/4][(s.off+i)%4], s.ID[(s.off+j)/4][(s.off+j)%4] = s.ID[(s.off+j)/4][(s.off+j)%4], s.ID[(s.off+i)/4][(s.off+i)%4]
+	s.Name[(s.off+i)/4][(s.off+i)%4], s.Name[(s.off+j)/4][(s.off+j)%4] = s.Name[(s.off+j)/4][(s.off+j)%4], s.Name[(s.off+i)/4][(s.off+i)%4]
+	s.deleted[(s.off+i)/4][(s.off+i)%4], s.deleted[(s.off+j)/4][(s.off+j)%4] = s.deleted[(s.off+j)/4][(s.off+j)%4], s.deleted[(s.off+i)/4][(s.off+i)%4]
+}
+
+func (s UserBlocks) CopyWithin(dst, src, n int) {
+	if dst < 0 || src < 0 || n < 0 || s.cap-n < dst || s.cap-n < src {
+		panic(fmt.Sprintf("slice bounds out of range [%d:%d] or [%d:%d] with capacity %d", dst, dst+n, src, src+n, s.cap))
+	}
+	if dst < src {
+		for k := 0; k < n; k++ {
+			s.ID[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.ID[(s.off+src+k)/4][(s.off+src+k)%4]
+			s.Name[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.Name[(s.off+src+k)/4][(s.off+src+k)%4]
+			s.deleted[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.deleted[(s.off+src+k)/4][(s.off+src+k)%4]
+		}
+		return
+	}
+	for k := n - 1; k >= 0; k-- {
+		s.ID[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.ID[(s.off+src+k)/4][(s.off+src+k)%4]
+		s.Name[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.Name[(s.off+src+k)/4][(s.off+src+k)%4]
+		s.deleted[(s.off+dst+k)/4][(s.off+dst+k)%4] = s.deleted[(s.off+src+k)/4][(s.off+src+k)%4]
+	}
+}
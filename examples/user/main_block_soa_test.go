@@ -0,0 +1,59 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ichiban/soa"
+	"github.com/ichiban/soa/soatest"
+)
+
+var _ soa.Slice[UserBlocks, User] = UserBlocks{}
+
+func TestUserBlocks(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	t.Run("round trip", func(t *testing.T) {
+		s := soa.Make[UserBlocks](3, 3)
+		for i := range s.Len() {
+			want := soatest.Value[User](r)
+			s.Set(i, want)
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.ID, want.ID) {
+				t.Errorf("ID: got %v, want %v", got.ID, want.ID)
+			}
+			if !reflect.DeepEqual(got.Name, want.Name) {
+				t.Errorf("Name: got %v, want %v", got.Name, want.Name)
+			}
+			if !reflect.DeepEqual(got.deleted, want.deleted) {
+				t.Errorf("deleted: got %v, want %v", got.deleted, want.deleted)
+			}
+		}
+	})
+
+	t.Run("refs", func(t *testing.T) {
+		s := soa.Make[UserBlocks](3, 3)
+		for i, ref := range s.Refs() {
+			want := soatest.Value[User](r)
+			*ref.ID = want.ID
+			*ref.Name = want.Name
+			*ref.deleted = want.deleted
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.ID, want.ID) {
+				t.Errorf("ID: got %v, want %v", got.ID, want.ID)
+			}
+			if !reflect.DeepEqual(got.Name, want.Name) {
+				t.Errorf("Name: got %v, want %v", got.Name, want.Name)
+			}
+			if !reflect.DeepEqual(got.deleted, want.deleted) {
+				t.Errorf("deleted: got %v, want %v", got.deleted, want.deleted)
+			}
+		}
+	})
+
+	soatest.Run[UserBlocks](t, func() User {
+		return soatest.Value[User](r)
+	})
+}
@@ -2,6 +2,9 @@
 package main
 
 import (
+	"encoding/json"
+	"fmt"
+	"iter"
 	"slices"
 )
 
@@ -11,6 +14,12 @@ type UserSlice struct {
 	deleted []bool
 }
 
+type UserSliceRef struct {
+	ID      *int
+	Name    *string
+	deleted *bool
+}
+
 func (s UserSlice) Get(i int) User {
 	var t User
 	t.ID = s.ID[i]
@@ -25,8 +34,95 @@ func (s UserSlice) Set(i int, t User) {
 	s.deleted[i] = t.deleted
 }
 
+func UserSliceFromSlice(es []User) UserSlice {
+	var s UserSlice
+	s = s.Grow(len(es)).Slice(0, len(es), len(es))
+	for i := range es {
+		s.ID[i] = es[i].ID
+	}
+	for i := range es {
+		s.Name[i] = es[i].Name
+	}
+	for i := range es {
+		s.deleted[i] = es[i].deleted
+	}
+	return s
+}
+
+func (s UserSlice) ToSlice() []User {
+	es := make([]User, s.Len())
+	for i := range es {
+		es[i].ID = s.ID[i]
+	}
+	for i := range es {
+		es[i].Name = s.Name[i]
+	}
+	for i := range es {
+		es[i].deleted = s.deleted[i]
+	}
+	return es
+}
+
+func (s UserSlice) Ref(i int) UserSliceRef {
+	return UserSliceRef{
+		ID:      &s.ID[i],
+		Name:    &s.Name[i],
+		deleted: &s.deleted[i],
+	}
+}
+
+func (s UserSlice) Refs() iter.Seq2[int, UserSliceRef] {
+	return func(yield func(int, UserSliceRef) bool) {
+		for i := 0; i < s.Len(); i++ {
+			if !yield(i, s.Ref(i)) {
+				return
+			}
+		}
+	}
+}
+
+func (s UserSlice) GetID(i int) int {
+	return s.ID[i]
+}
+
+func (s UserSlice) SetID(i int, v int) {
+	s.ID[i] = v
+}
+
+func (s UserSlice) IDSeq() iter.Seq2[int, int] {
+	return func(yield func(int, int) bool) {
+		for i, v := range s.ID[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
+func (s UserSlice) GetName(i int) string {
+	return s.Name[i]
+}
+
+func (s UserSlice) SetName(i int, v string) {
+	s.Name[i] = v
+}
+
+func (s UserSlice) NameSeq() iter.Seq2[int, string] {
+	return func(yield func(int, string) bool) {
+		for i, v := range s.Name[:s.Len()] {
+			if !yield(i, v) {
+				return
+			}
+		}
+	}
+}
+
 func (s UserSlice) Len() int {
-	return len(s.ID)
+	return min(
+		len(s.ID),
+		len(s.Name),
+		len(s.deleted),
+	)
 }
 
 func (s UserSlice) Cap() int {
@@ -52,3 +148,72 @@ func (s UserSlice) Grow(n int) UserSlice {
 		deleted: slices.Grow(s.deleted, n),
 	}
 }
+
+func (s UserSlice) Columns() ([]int, []string, []bool) {
+	n := s.Len()
+	return s.ID[:n], s.Name[:n], s.deleted[:n]
+}
+
+func (s UserSlice) Swap(i, j int) {
+	s.ID[i], s.ID[j] = s.ID[j], s.ID[i]
+	s.Name[i], s.Name[j] = s.Name[j], s.Name[i]
+	s.deleted[i], s.deleted[j] = s.deleted[j], s.deleted[i]
+}
+
+func (s UserSlice) CopyWithin(dst, src, n int) {
+	copy(s.ID[dst:dst+n], s.ID[src:src+n])
+	copy(s.Name[dst:dst+n], s.Name[src:src+n])
+	copy(s.deleted[dst:dst+n], s.deleted[src:src+n])
+}
+
+// MarshalJSON encodes the SoA slice as an object of arrays. i.e. {"X":[1,2],"Y":[3,4]}
+func (s UserSlice) MarshalJSON() ([]byte, error) {
+	n := s.Len()
+	if n == 0 {
+		// Empty columns are encoded as empty arrays rather than null.
+		return []byte(`{"ID":[],"Name":[],"deleted":[]}`), nil
+	}
+	cID := s.ID[:n]
+	cName := s.Name[:n]
+	cdeleted := s.deleted[:n]
+	return json.Marshal(struct {
+		CID      []int    `json:"ID"`
+		CName    []string `json:"Name"`
+		Cdeleted []bool   `json:"deleted"`
+	}{
+		CID:      cID,
+		CName:    cName,
+		Cdeleted: cdeleted,
+	})
+}
+
+// UnmarshalJSON decodes the SoA slice from an object of arrays. All the arrays must have the same length.
+func (s *UserSlice) UnmarshalJSON(b []byte) error {
+	var v struct {
+		CID      []int    `json:"ID"`
+		CName    []string `json:"Name"`
+		Cdeleted []bool   `json:"deleted"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	n := len(v.CID)
+	if len(v.CID) != n {
+		return fmt.Errorf("column ID has %d elements, want %d", len(v.CID), n)
+	}
+	if len(v.CName) != n {
+		return fmt.Errorf("column Name has %d elements, want %d", len(v.CName), n)
+	}
+	if len(v.Cdeleted) != n {
+		return fmt.Errorf("column deleted has %d elements, want %d", len(v.Cdeleted), n)
+	}
+	cID := v.CID
+	cName := v.CName
+	cdeleted := v.Cdeleted
+	*s = UserSlice{
+		ID:      cID[:n:n],
+		Name:    cName[:n:n],
+		deleted: cdeleted[:n:n],
+	}
+	return nil
+}
@@ -0,0 +1,85 @@
+// Code generated by soagen; DO NOT EDIT.
+package main
+
+import (
+	"encoding/json"
+	"math/rand"
+	"reflect"
+	"testing"
+
+	"github.com/ichiban/soa"
+	"github.com/ichiban/soa/soatest"
+)
+
+var _ soa.Slice[UserSlice, User] = UserSlice{}
+
+func TestUserSlice(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+
+	t.Run("round trip", func(t *testing.T) {
+		s := soa.Make[UserSlice](3, 3)
+		for i := range s.Len() {
+			want := soatest.Value[User](r)
+			s.Set(i, want)
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.ID, want.ID) {
+				t.Errorf("ID: got %v, want %v", got.ID, want.ID)
+			}
+			if !reflect.DeepEqual(got.Name, want.Name) {
+				t.Errorf("Name: got %v, want %v", got.Name, want.Name)
+			}
+			if !reflect.DeepEqual(got.deleted, want.deleted) {
+				t.Errorf("deleted: got %v, want %v", got.deleted, want.deleted)
+			}
+		}
+	})
+
+	t.Run("refs", func(t *testing.T) {
+		s := soa.Make[UserSlice](3, 3)
+		for i, ref := range s.Refs() {
+			want := soatest.Value[User](r)
+			*ref.ID = want.ID
+			*ref.Name = want.Name
+			*ref.deleted = want.deleted
+			got := s.Get(i)
+			if !reflect.DeepEqual(got.ID, want.ID) {
+				t.Errorf("ID: got %v, want %v", got.ID, want.ID)
+			}
+			if !reflect.DeepEqual(got.Name, want.Name) {
+				t.Errorf("Name: got %v, want %v", got.Name, want.Name)
+			}
+			if !reflect.DeepEqual(got.deleted, want.deleted) {
+				t.Errorf("deleted: got %v, want %v", got.deleted, want.deleted)
+			}
+		}
+	})
+
+	t.Run("json", func(t *testing.T) {
+		for _, n := range []int{0, 5} {
+			want := make([]User, n)
+			for i := range want {
+				want[i] = soatest.Value[User](r)
+			}
+			b, err := json.Marshal(soa.FromSlice[UserSlice](want))
+			if err != nil {
+				t.Fatal(err)
+			}
+			var s UserSlice
+			if err := json.Unmarshal(b, &s); err != nil {
+				t.Fatal(err)
+			}
+			if got := soa.ToSlice(s); !reflect.DeepEqual(got, want) {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		}
+
+		var s UserSlice
+		if err := json.Unmarshal([]byte(`{"Name":[null]}`), &s); err == nil || err.Error() != "column Name has 1 elements, want 0" {
+			t.Errorf("got %v, want the error of the column length", err)
+		}
+	})
+
+	soatest.Run[UserSlice](t, func() User {
+		return soatest.Value[User](r)
+	})
+}
@@ -0,0 +1,230 @@
+// Package diff computes line-based unified diffs.
+package diff
+
+import (
+	"fmt"
+	"strings"
+)
+
+// context is the number of unchanged lines around changes in a hunk.
+const context = 3
+
+// Unified returns the unified diff from a to b labeled with oldName and newName. It returns "" if a and b are equal.
+func Unified(oldName, newName string, a, b []byte) string {
+	es := edits(lines(string(a)), lines(string(b)))
+	hs := hunks(es)
+	if len(hs) == 0 {
+		return ""
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "--- %s\n+++ %s\n", oldName, newName)
+	for _, h := range hs {
+		h.writeTo(&sb)
+	}
+	return sb.String()
+}
+
+// lines splits s into lines including their line endings.
+func lines(s string) []string {
+	var ls []string
+	for s != "" {
+		i := strings.IndexByte(s, '\n') + 1
+		if i == 0 {
+			i = len(s)
+		}
+		ls = append(ls, s[:i])
+		s = s[i:]
+	}
+	return ls
+}
+
+// edit is a line of a unified diff.
+type edit struct {
+	// Op is ' ' for an unchanged line, '-' for a deleted line, or '+' for an inserted line.
+	Op   byte
+	Line string
+}
+
+// edits returns the shortest edit script from a to b with Myers' algorithm.
+func edits(a, b []string) []edit {
+	// The common prefix and suffix don't need to be searched.
+	var pre, suf int
+	for pre < len(a) && pre < len(b) && a[pre] == b[pre] {
+		pre++
+	}
+	for suf < len(a)-pre && suf < len(b)-pre && a[len(a)-1-suf] == b[len(b)-1-suf] {
+		suf++
+	}
+
+	es := make([]edit, 0, len(a)+len(b))
+	for _, l := range a[:pre] {
+		es = append(es, edit{Op: ' ', Line: l})
+	}
+	es = append(es, middle(a[pre:len(a)-suf], b[pre:len(b)-suf])...)
+	for _, l := range a[len(a)-suf:] {
+		es = append(es, edit{Op: ' ', Line: l})
+	}
+	return es
+}
+
+// middle returns the shortest edit script from a to b which share neither the first nor the last line.
+func middle(a, b []string) []edit {
+	n, m := len(a), len(b)
+	if n == 0 || m == 0 {
+		es := make([]edit, 0, n+m)
+		for _, l := range a {
+			es = append(es, edit{Op: '-', Line: l})
+		}
+		for _, l := range b {
+			es = append(es, edit{Op: '+', Line: l})
+		}
+		return es
+	}
+
+	// v[off+k] is the furthest x on the diagonal k = x - y. trace[d] is v before the d-th step.
+	off := n + m
+	v := make([]int, 2*off+2)
+	var trace [][]int
+	for d := 0; ; d++ {
+		trace = append(trace, append([]int(nil), v[off-d:off+d+2]...))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[off+k-1] < v[off+k+1] {
+				x = v[off+k+1]
+			} else {
+				x = v[off+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x++
+				y++
+			}
+			v[off+k] = x
+			if x >= n && y >= m {
+				return backtrack(a, b, trace, d, k)
+			}
+		}
+	}
+}
+
+// backtrack follows the trace back from the d-th step on the diagonal k to the start.
+func backtrack(a, b []string, trace [][]int, d, k int) []edit {
+	// at returns the furthest x on the diagonal k before the d-th step.
+	at := func(d, k int) int {
+		return trace[d][k+d]
+	}
+
+	es := make([]edit, 0, len(a)+len(b))
+	x, y := len(a), len(b)
+	for ; d > 0; d-- {
+		var pk int
+		if k == -d || k != d && at(d, k-1) < at(d, k+1) {
+			pk = k + 1
+		} else {
+			pk = k - 1
+		}
+		px := at(d, pk)
+		py := px - pk
+		for x > px && y > py {
+			x--
+			y--
+			es = append(es, edit{Op: ' ', Line: a[x]})
+		}
+		if pk == k+1 {
+			y--
+			es = append(es, edit{Op: '+', Line: b[y]})
+		} else {
+			x--
+			es = append(es, edit{Op: '-', Line: a[x]})
+		}
+		k = pk
+	}
+	for x > 0 {
+		x--
+		es = append(es, edit{Op: ' ', Line: a[x]})
+	}
+
+	for i, j := 0, len(es)-1; i < j; i, j = i+1, j-1 {
+		es[i], es[j] = es[j], es[i]
+	}
+	return es
+}
+
+// hunk is a group of changes with the surrounding unchanged lines.
+type hunk struct {
+	// OldLine and NewLine are the 1-based line numbers of the first lines of the hunk.
+	OldLine, NewLine int
+	Edits            []edit
+}
+
+// hunks groups the edits into hunks. Changes separated by at most 2*context unchanged lines share a hunk.
+func hunks(es []edit) []hunk {
+	var (
+		hs         []hunk
+		start, end = -1, -1
+	)
+	flush := func() {
+		if start < 0 {
+			return
+		}
+		h := hunk{OldLine: 1, NewLine: 1, Edits: es[start:end]}
+		for _, e := range es[:start] {
+			if e.Op != '+' {
+				h.OldLine++
+			}
+			if e.Op != '-' {
+				h.NewLine++
+			}
+		}
+		hs = append(hs, h)
+	}
+	for i, e := range es {
+		if e.Op == ' ' {
+			continue
+		}
+		// end already includes the context after the last change.
+		if start >= 0 && i-end > context {
+			flush()
+			start = -1
+		}
+		if start < 0 {
+			start = max(0, i-context)
+		}
+		end = min(len(es), i+1+context)
+	}
+	flush()
+	return hs
+}
+
+func (h hunk) writeTo(sb *strings.Builder) {
+	var o, n int
+	for _, e := range h.Edits {
+		if e.Op != '+' {
+			o++
+		}
+		if e.Op != '-' {
+			n++
+		}
+	}
+	fmt.Fprintf(sb, "@@ -%s +%s @@\n", span(h.OldLine, o), span(h.NewLine, n))
+	for _, e := range h.Edits {
+		sb.WriteByte(e.Op)
+		sb.WriteString(e.Line)
+		if !strings.HasSuffix(e.Line, "\n") {
+			sb.WriteString("\n\\ No newline at end of file\n")
+		}
+	}
+}
+
+// span formats the range of a hunk. An empty range starts at the line before it.
+func span(line, n int) string {
+	switch n {
+	case 0:
+		return fmt.Sprintf("%d,0", line-1)
+	case 1:
+		return fmt.Sprintf("%d", line)
+	default:
+		return fmt.Sprintf("%d,%d", line, n)
+	}
+}
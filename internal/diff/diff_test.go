@@ -0,0 +1,202 @@
+package diff
+
+import (
+	"math/rand"
+	"slices"
+	"testing"
+)
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		title string
+		a, b  string
+		diff  string
+	}{
+		{title: "equal", a: "a\nb\n", b: "a\nb\n"},
+		{title: "empty"},
+		{
+			title: "new",
+			b:     "a\nb\n",
+			diff: `--- old
++++ new
+@@ -0,0 +1,2 @@
++a
++b
+`,
+		},
+		{
+			title: "deleted",
+			a:     "a\n",
+			diff: `--- old
++++ new
+@@ -1 +0,0 @@
+-a
+`,
+		},
+		{
+			title: "changed",
+			a:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:     "1\n2\n3\n4\nfive\n6\n7\n8\n9\n",
+			diff: `--- old
++++ new
+@@ -2,7 +2,7 @@
+ 2
+ 3
+ 4
+-5
++five
+ 6
+ 7
+ 8
+`,
+		},
+		{
+			title: "separate hunks",
+			a:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n",
+			b:     "0\n1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n12\n",
+			diff: `--- old
++++ new
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+@@ -8,5 +9,4 @@
+ 8
+ 9
+ 10
+-11
+ 12
+`,
+		},
+		{
+			title: "6 unchanged lines apart",
+			a:     "1\n2\n3\n4\n5\n6\n7\n8\n",
+			b:     "one\n2\n3\n4\n5\n6\n7\neight\n",
+			diff: `--- old
++++ new
+@@ -1,8 +1,8 @@
+-1
++one
+ 2
+ 3
+ 4
+ 5
+ 6
+ 7
+-8
++eight
+`,
+		},
+		{
+			title: "7 unchanged lines apart",
+			a:     "1\n2\n3\n4\n5\n6\n7\n8\n9\n",
+			b:     "one\n2\n3\n4\n5\n6\n7\n8\nnine\n",
+			diff: `--- old
++++ new
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -6,4 +6,4 @@
+ 6
+ 7
+ 8
+-9
++nine
+`,
+		},
+		{
+			title: "no newline at end of file",
+			a:     "a\nb",
+			b:     "a\nb\n",
+			diff: `--- old
++++ new
+@@ -1,2 +1,2 @@
+ a
+-b
+\ No newline at end of file
++b
+`,
+		},
+		{
+			title: "interleaved",
+			a:     "a\nb\nc\nd\n",
+			b:     "b\nx\nc\ny\n",
+			diff: `--- old
++++ new
+@@ -1,4 +1,4 @@
+-a
+ b
++x
+ c
+-d
++y
+`,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			if got := Unified("old", "new", []byte(test.a), []byte(test.b)); got != test.diff {
+				t.Errorf("got:\n%s\nwant:\n%s", got, test.diff)
+			}
+		})
+	}
+}
+
+func TestEdits(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	random := func() []string {
+		ls := make([]string, r.Intn(10))
+		for i := range ls {
+			ls[i] = string(rune('a' + r.Intn(3)))
+		}
+		return ls
+	}
+
+	for range 1000 {
+		a, b := random(), random()
+		es := edits(a, b)
+
+		var x, y []string
+		changes := 0
+		for _, e := range es {
+			if e.Op != '+' {
+				x = append(x, e.Line)
+			}
+			if e.Op != '-' {
+				y = append(y, e.Line)
+			}
+			if e.Op != ' ' {
+				changes++
+			}
+		}
+		if !slices.Equal(x, a) || !slices.Equal(y, b) {
+			t.Fatalf("edits(%q, %q) = %q", a, b, es)
+		}
+		if want := len(a) + len(b) - 2*lcs(a, b); changes != want {
+			t.Fatalf("edits(%q, %q) has %d changes, want %d", a, b, changes, want)
+		}
+	}
+}
+
+// lcs returns the length of the longest common subsequence of a and b.
+func lcs(a, b []string) int {
+	dp := make([][]int, len(a)+1)
+	for i := range dp {
+		dp[i] = make([]int, len(b)+1)
+	}
+	for i := range a {
+		for j := range b {
+			if a[i] == b[j] {
+				dp[i+1][j+1] = dp[i][j] + 1
+			} else {
+				dp[i+1][j+1] = max(dp[i][j+1], dp[i+1][j])
+			}
+		}
+	}
+	return dp[len(a)][len(b)]
+}
@@ -0,0 +1,29 @@
+package gen
+
+import (
+	"fmt"
+	"go/token"
+)
+
+// Error is an error in the input at a position.
+type Error struct {
+	// Pos is invalid if the error isn't specific to a position. i.e. a missing target
+	Pos token.Position
+	Err error
+}
+
+// errorf returns an Error at pos with a formatted message.
+func errorf(pos token.Position, format string, a ...any) *Error {
+	return &Error{Pos: pos, Err: fmt.Errorf(format, a...)}
+}
+
+func (e *Error) Error() string {
+	if !e.Pos.IsValid() {
+		return e.Err.Error()
+	}
+	return fmt.Sprintf("%s: %v", e.Pos, e.Err)
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
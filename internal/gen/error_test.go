@@ -0,0 +1,188 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestError(t *testing.T) {
+	tests := []struct {
+		title  string
+		load   func() error
+		line   int
+		column int
+		msg    string
+	}{
+		{
+			title: "file",
+			load: func() error {
+				_, err := ParseFile("testdata/tags.go", Config{}, "UnknownOption")
+				return err
+			},
+			line:   12,
+			column: 2,
+			msg:    `unknown option "unknown"`,
+		},
+		{
+			title: "package",
+			load: func() error {
+				_, err := LoadPackage("testdata/pkg", Config{}, "github.com/ichiban/soa/internal/gen/testdata/models.Secret")
+				return err
+			},
+			line:   22,
+			column: 2,
+			msg:    "unexported field token of package github.com/ichiban/soa/internal/gen/testdata/models cannot be accessed",
+		},
+		{
+			title: "recursive",
+			load: func() error {
+				_, err := ParseFile("testdata/suspicious.go", Config{Flatten: true}, "Node")
+				return err
+			},
+			line:   23,
+			column: 2,
+			msg:    "field Parent of recursive type Node cannot be flattened",
+		},
+		{
+			title: "non struct target",
+			load: func() error {
+				_, err := ParseFile("testdata/suspicious.go", Config{}, "Celsius")
+				return err
+			},
+			line:   26,
+			column: 6,
+			msg:    "type Celsius is not a struct",
+		},
+		{
+			title: "missing target",
+			load: func() error {
+				_, err := ParseFile("testdata/suspicious.go", Config{}, "Missing")
+				return err
+			},
+			msg: "type Missing not found",
+		},
+		{
+			title: "non struct target in package",
+			load: func() error {
+				_, err := LoadPackage("testdata/pkg", Config{}, "Note")
+				return err
+			},
+			line:   7,
+			column: 6,
+			msg:    "type Note is not a struct",
+		},
+		{
+			title: "missing target in package",
+			load: func() error {
+				_, err := LoadPackage("testdata/pkg", Config{}, "Missing")
+				return err
+			},
+			msg: "type Missing not found",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			var e *Error
+			if !errors.As(test.load(), &e) {
+				t.Fatal("not an Error")
+			}
+			if e.Pos.Line != test.line || e.Pos.Column != test.column {
+				t.Errorf("got %d:%d, want %d:%d", e.Pos.Line, e.Pos.Column, test.line, test.column)
+			}
+			if e.Err.Error() != test.msg {
+				t.Errorf("got %q, want %q", e.Err.Error(), test.msg)
+			}
+		})
+	}
+}
+
+func TestError_Joined(t *testing.T) {
+	_, err := ParseFile("testdata/suspicious.go", Config{}, "Problems", "Missing")
+	j, ok := err.(interface{ Unwrap() []error })
+	if !ok {
+		t.Fatalf("not joined: %v", err)
+	}
+	var got []string
+	for _, e := range j.Unwrap() {
+		// Problems of the fields of a struct are joined again.
+		if j, ok := e.(interface{ Unwrap() []error }); ok {
+			for _, e := range j.Unwrap() {
+				got = append(got, e.Error())
+			}
+			continue
+		}
+		got = append(got, e.Error())
+	}
+	want := []string{
+		`testdata/suspicious.go:30:2: unknown option "unknown"`,
+		"testdata/suspicious.go:31:2: field Y is not a struct",
+		"type Missing not found",
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("got %q, want %q", got, want)
+	}
+}
+
+func TestFile_Warnings(t *testing.T) {
+	tests := []struct {
+		title    string
+		load     func() (File, error)
+		warnings []string
+	}{
+		{
+			title: "blank",
+			load: func() (File, error) {
+				return ParseFile("testdata/suspicious.go", Config{}, "Blank")
+			},
+			warnings: []string{"suspicious.go:5:2: blank field is not stored"},
+		},
+		{
+			title: "embedded interface",
+			load: func() (File, error) {
+				return ParseFile("testdata/suspicious.go", Config{}, "EmbeddedInterface")
+			},
+			warnings: []string{"suspicious.go:13:2: methods of embedded interface Stringer are not promoted to the SoA slice"},
+		},
+		{
+			title: "embedded struct",
+			load: func() (File, error) {
+				return ParseFile("testdata/embedded.go", Config{})
+			},
+		},
+		{
+			title: "package",
+			load: func() (File, error) {
+				return LoadPackage("testdata/pkg", Config{}, "github.com/ichiban/soa/internal/gen/testdata/models.Stream")
+			},
+			warnings: []string{
+				"models.go:28:5: methods of embedded interface io.Reader are not promoted to the SoA slice",
+				"models.go:30:2: blank field is not stored",
+			},
+		},
+		{
+			title: "along with errors",
+			load: func() (File, error) {
+				return ParseFile("testdata/suspicious.go", Config{}, "Problems")
+			},
+			warnings: []string{"suspicious.go:29:2: blank field is not stored"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			f, _ := test.load()
+			var got []string
+			for _, w := range f.Warnings {
+				// Packages are loaded with absolute paths.
+				got = append(got, fmt.Sprintf("%s:%d:%d: %v", filepath.Base(w.Pos.Filename), w.Pos.Line, w.Pos.Column, w.Err))
+			}
+			if !slices.Equal(got, test.warnings) {
+				t.Errorf("got %q, want %q", got, test.warnings)
+			}
+		})
+	}
+}
@@ -1,44 +1,160 @@
 package gen
 
 import (
-	"bytes"
-	_ "embed"
+	"errors"
+	"fmt"
 	"go/ast"
 	"go/parser"
 	"go/printer"
 	"go/token"
 	"io"
+	"path"
+	"reflect"
 	"slices"
+	"strconv"
 	"strings"
-	"text/template"
-
-	"golang.org/x/tools/imports"
+	"unicode"
+	"unicode/utf8"
 )
 
-//go:embed soa.go.tmpl
-var soaTemplate string
-
-var fileTemplate = template.Must(template.New("").Funcs(map[string]any{
-	"join": strings.Join,
-}).Parse(soaTemplate))
-
 type File struct {
 	PackageName string
 	Imports     []Import
 	Structs     []Struct
+	// Warnings are the suspicious fields of the structs which don't prevent the generation.
+	Warnings []*Error
+
+	// exact is true if Imports are exactly the packages the fields refer to. The code generated by the default
+	// template is formatted as it is without resolving the imports then.
+	exact bool
 }
 
-func (f *File) WriteTo(w io.Writer) (int64, error) {
-	var buf bytes.Buffer
-	_ = fileTemplate.Execute(&buf, f)
+// StdImports returns the standard packages the generated code depends on except the ones in Imports.
+func (f *File) StdImports() []string {
+	var blocked, unblocked, json, bin, arrow, sql bool
+	for _, s := range f.Structs {
+		if s.BlockSize > 0 {
+			blocked = true
+		} else {
+			unblocked = true
+		}
+		if s.JSON != "" {
+			json = true
+		}
+		if s.Binary {
+			bin = true
+		}
+		if s.Arrow {
+			arrow = true
+		}
+		if s.SQL {
+			sql = true
+		}
+	}
+	var ps []string
+	if bin {
+		ps = append(ps, "bytes")
+	}
+	if sql {
+		ps = append(ps, "database/sql")
+	}
+	if json {
+		ps = append(ps, "encoding/json")
+	}
+	if blocked || json || sql {
+		ps = append(ps, "fmt")
+	}
+	if len(f.Structs) > 0 {
+		ps = append(ps, "iter")
+	}
+	if bin || arrow {
+		ps = append(ps, "io")
+	}
+	if unblocked {
+		ps = append(ps, "slices")
+	}
+	return slices.DeleteFunc(ps, f.imported)
+}
 
-	b, err := imports.Process("", buf.Bytes(), nil)
-	if err != nil {
-		return 0, err
+// StdTestImports returns the standard packages the generated test depends on except the ones in Imports.
+func (f *File) StdTestImports() []string {
+	var ps []string
+	if slices.ContainsFunc(f.Structs, func(s Struct) bool {
+		return s.Binary && len(s.TypeParams) == 0
+	}) {
+		ps = append(ps, "bytes")
+	}
+	if slices.ContainsFunc(f.Structs, func(s Struct) bool {
+		return s.JSON != "" && len(s.TypeParams) == 0
+	}) {
+		ps = append(ps, "encoding/json")
+	}
+	return slices.DeleteFunc(append(ps, "math/rand", "reflect", "testing"), f.imported)
+}
+
+// LibImports returns the packages of this module the generated code depends on except the ones in Imports.
+func (f *File) LibImports() []string {
+	var ps []string
+	if slices.ContainsFunc(f.Structs, func(s Struct) bool {
+		return s.Binary
+	}) {
+		ps = append(ps, "github.com/ichiban/soa/soabin")
+	}
+	if slices.ContainsFunc(f.Structs, func(s Struct) bool {
+		return s.Arrow
+	}) {
+		ps = append(ps, "github.com/ichiban/soa/soaarrow")
+	}
+	return slices.DeleteFunc(ps, f.imported)
+}
+
+// LibTestImports returns the packages of this module the generated test depends on except the ones in Imports.
+func (f *File) LibTestImports() []string {
+	ps := []string{"github.com/ichiban/soa", "github.com/ichiban/soa/soatest"}
+	if slices.ContainsFunc(f.Structs, func(s Struct) bool {
+		return s.Binary && len(s.TypeParams) == 0
+	}) {
+		ps = append(ps, "github.com/ichiban/soa/soabin")
 	}
+	return slices.DeleteFunc(ps, f.imported)
+}
+
+// imported checks if the package of the path is in Imports by the name the templates refer to it.
+func (f *File) imported(p string) bool {
+	return slices.ContainsFunc(f.Imports, func(i Import) bool {
+		return i.Path == strconv.Quote(p) && (i.Name == "" || i.Name == path.Base(p))
+	})
+}
+
+// templateImports are the names and the paths of the packages the default templates may import. Packages of the fields
+// are imported with other names if they're different packages of the same names.
+var templateImports = func() map[string]string {
+	// Every feature is enabled so that every import is listed.
+	f := File{Structs: []Struct{{JSON: JSONColumns, Binary: true, Arrow: true, SQL: true}, {BlockSize: 1}}}
+	ps := slices.Concat(f.StdImports(), f.StdTestImports(), f.LibImports(), f.LibTestImports())
+	m := make(map[string]string, len(ps))
+	for _, p := range ps {
+		m[path.Base(p)] = p
+	}
+	return m
+}()
+
+// reserved checks if the name is taken by a package of another path the default templates may import.
+func reserved(name, path string) bool {
+	p, ok := templateImports[name]
+	return ok && p != path
+}
 
-	n, err := w.Write(b)
-	return int64(n), err
+// WriteTo writes the code generated by the default template.
+func (f *File) WriteTo(w io.Writer) (int64, error) {
+	return defaultTemplate.Execute(w, f)
+}
+
+// WriteTestTo writes the test of the SoA slices generated by WriteTo. It asserts they implement soa.Slice, sets and
+// gets random elements to check every field, round-trips them through JSON and the binary format if the SoA slices are encoded to them, and
+// runs soatest.Run. SoA slices of generic structs are not tested.
+func (f *File) WriteTestTo(w io.Writer) (int64, error) {
+	return defaultTestTemplate.Execute(w, f)
 }
 
 type Import struct {
@@ -47,35 +163,531 @@ type Import struct {
 }
 
 type Struct struct {
-	Name      string
-	SliceName string
-	Fields    []Field
+	Name       string
+	Package    string
+	SliceName  string
+	TypeParams []TypeParam
+	Fields     []Field
+	// BlockSize is the number of elements stored in a block of each column. If positive, the SoA slice has the
+	// array-of-structures-of-arrays layout. i.e. [][8]T. Columns isn't generated for the layout.
+	BlockSize int
+	// JSON is the form of the JSON encoding of the SoA slice, either JSONColumns or JSONRows. If empty, MarshalJSON and
+	// UnmarshalJSON are not generated.
+	JSON string
+	// Binary generates MarshalBinary, UnmarshalBinary, WriteTo, and ReadFrom which encode the SoA slice in the format of
+	// soabin.
+	Binary bool
+	// Arrow generates WriteArrow and ReadArrow which write and read the SoA slice in the Arrow IPC streaming format.
+	Arrow bool
+	// SQL generates ScanRows which appends the rows of database/sql to the SoA slice.
+	SQL bool
+}
+
+// Forms of the JSON encoding of SoA slices.
+const (
+	// JSONColumns encodes an SoA slice as an object of arrays. i.e. {"X":[1,2],"Y":[3,4]}
+	JSONColumns = "columns"
+	// JSONRows encodes an SoA slice as an array of objects. i.e. [{"X":1,"Y":3},{"X":2,"Y":4}]
+	JSONRows = "rows"
+)
+
+// Type returns the element type instantiated with its type parameters. i.e. Pair[K, V] or models.Pair[K, V]
+func (s Struct) Type() string {
+	if s.Package != "" {
+		return s.Package + "." + s.Name + s.args()
+	}
+	return s.Name + s.args()
+}
+
+// SliceType returns the SoA slice type instantiated with its type parameters. i.e. PairSlice[K, V]
+func (s Struct) SliceType() string {
+	return s.SliceName + s.args()
+}
+
+// RefName returns the name of the reference type which points to the fields of an element. i.e. PairSliceRef
+// It's derived from the slice name so that SoA slices of the same struct have their own reference types.
+func (s Struct) RefName() string {
+	return s.SliceName + "Ref"
+}
+
+// RefType returns the reference type instantiated with its type parameters. i.e. PairSliceRef[K, V]
+func (s Struct) RefType() string {
+	return s.RefName() + s.args()
+}
+
+// Params returns the type parameter list. i.e. [K comparable, V any]
+func (s Struct) Params() string {
+	if len(s.TypeParams) == 0 {
+		return ""
+	}
+	ps := make([]string, len(s.TypeParams))
+	for i, p := range s.TypeParams {
+		ps[i] = strings.Join(p.Names, ", ") + " " + p.Constraint
+	}
+	return "[" + strings.Join(ps, ", ") + "]"
+}
+
+// ColumnType returns the element type of the column.
+func (s Struct) ColumnType(f Field) string {
+	if len(f.Fields) > 0 {
+		return s.GroupName(f) + s.args()
+	}
+	return f.Type
+}
+
+// GroupName returns the name of the struct for the grouped column. i.e. PointSliceHot
+func (s Struct) GroupName(f Field) string {
+	r, n := utf8.DecodeRuneInString(f.Name)
+	return s.SliceName + string(unicode.ToUpper(r)) + f.Name[n:]
+}
+
+// accessor returns the name of a method for the column name. The method is exported only if the column is.
+func accessor(prefix, name, suffix string) string {
+	if prefix == "" {
+		return name + suffix
+	}
+	r, n := utf8.DecodeRuneInString(name)
+	if !token.IsExported(name) {
+		prefix = strings.ToLower(prefix)
+	}
+	return prefix + string(unicode.ToUpper(r)) + name[n:] + suffix
+}
+
+// Index returns the index expression of the i-th element of a column. i.e. [i] or [(s.off+i)/8][(s.off+i)%8]
+func (s Struct) Index(i string) string {
+	if s.BlockSize > 0 {
+		return fmt.Sprintf("[(s.off+%[1]s)/%[2]d][(s.off+%[1]s)%%%[2]d]", i, s.BlockSize)
+	}
+	return "[" + i + "]"
+}
+
+// SetSliceName names the SoA slice. The types generated for it, i.e. PointSliceRef and PointSliceHot, have to be unique.
+func (s *Struct) SetSliceName(name string) error {
+	t := *s
+	t.SliceName = name
+	if err := t.checkTypes(); err != nil {
+		return err
+	}
+	s.SliceName = name
+	return nil
+}
+
+// checkTypes checks if the names of the types generated for the SoA slice don't conflict with each other.
+func (s Struct) checkTypes() error {
+	ts := map[string]bool{s.SliceName: true, s.RefName(): true}
+	for _, f := range s.Fields {
+		if len(f.Fields) == 0 {
+			continue
+		}
+		n := s.GroupName(f)
+		if ts[n] {
+			return fmt.Errorf("group %s conflicts with type %s", f.Name, n)
+		}
+		ts[n] = true
+	}
+	return nil
+}
+
+// SetBlockSize makes the SoA slice store n elements in a block of each column.
+func (s *Struct) SetBlockSize(n int) error {
+	if n < 0 {
+		return fmt.Errorf("negative block size %d", n)
+	}
+	if n > 0 {
+		if len(s.Fields) == 0 {
+			return errors.New("block layout requires at least 1 column")
+		}
+		for _, f := range s.Fields {
+			switch f.Name {
+			case "off", "len", "cap":
+				return fmt.Errorf("column %s is reserved in block layout", f.Name)
+			}
+		}
+	}
+	s.BlockSize = n
+	return nil
+}
+
+// SetJSON makes the SoA slice encoded to and decoded from JSON in the form.
+func (s *Struct) SetJSON(form string) error {
+	switch form {
+	case "", JSONColumns, JSONRows:
+		s.JSON = form
+		return nil
+	default:
+		return fmt.Errorf("unknown JSON form %q", form)
+	}
+}
+
+// SetBinary makes the SoA slice encoded to and decoded from the compact binary columnar format of soabin. Columns of
+// types the format doesn't support, i.e. pointers, maps, and slices other than []byte, are rejected. Named types are
+// assumed to be supported since their underlying types and methods are unknown here.
+func (s *Struct) SetBinary(binary bool) error {
+	if binary {
+		for _, f := range s.Leaves() {
+			if !supports(f.Type, binaryType) {
+				return fmt.Errorf("unsupported type %s of column %s in the binary format", f.Type, f.Name)
+			}
+		}
+	}
+	s.Binary = binary
+	return nil
+}
+
+// SetArrow makes the SoA slice written and read in the Arrow IPC streaming format. Columns of types the format doesn't
+// support, i.e. pointers, maps, slices, and arrays, are rejected. Named types are assumed to be supported since their
+// underlying types are unknown here.
+func (s *Struct) SetArrow(arrow bool) error {
+	if arrow {
+		for _, f := range s.Leaves() {
+			if !supports(f.Type, arrowType) {
+				return fmt.Errorf("unsupported type %s of column %s in the Arrow format", f.Type, f.Name)
+			}
+		}
+	}
+	s.Arrow = arrow
+	return nil
+}
+
+// supports checks the type expression of a column with ok. A type which can't be parsed is left to the compiler.
+func supports(typ string, ok func(ast.Expr) bool) bool {
+	e, err := parser.ParseExpr(typ)
+	return err != nil || ok(e)
+}
+
+// binaryType checks if soabin may encode a column of the type.
+func binaryType(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.ArrayType:
+		if e.Len == nil {
+			return isIdent(e.Elt, "byte", "uint8")
+		}
+		return rawType(e.Elt)
+	case *ast.Ident:
+		return !isIdent(e, "any", "error")
+	case *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+// rawType checks if soabin may encode an element of an array as it is in memory.
+func rawType(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.ArrayType:
+		return e.Len != nil && rawType(e.Elt)
+	case *ast.Ident:
+		return !isIdent(e, "any", "error", "int", "uint", "uintptr", "string")
+	case *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+// arrowType checks if soaarrow may write a column of the type.
+func arrowType(e ast.Expr) bool {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return !isIdent(e, "any", "error", "uintptr", "complex64", "complex128")
+	case *ast.SelectorExpr, *ast.IndexExpr, *ast.IndexListExpr:
+		return true
+	default:
+		return false
+	}
+}
+
+// isIdent checks if e is one of the identifiers.
+func isIdent(e ast.Expr, names ...string) bool {
+	i, ok := e.(*ast.Ident)
+	return ok && slices.Contains(names, i.Name)
 }
 
+// SetSQL makes the SoA slice scan the rows of database/sql. The names of the fields in the rows have to be unique.
+func (s *Struct) SetSQL(sql bool) error {
+	if sql {
+		seen := map[string]bool{}
+		for _, f := range s.Leaves() {
+			n := f.DBName()
+			if n == "-" {
+				continue
+			}
+			if seen[n] {
+				return fmt.Errorf("duplicate db column %s", n)
+			}
+			seen[n] = true
+		}
+	}
+	s.SQL = sql
+	return nil
+}
+
+// Leaves returns the columns and the fields of the grouped columns, each of which stores a field of the element.
+func (s Struct) Leaves() []Field {
+	var fs []Field
+	for _, f := range s.Fields {
+		if len(f.Fields) > 0 {
+			fs = append(fs, f.Fields...)
+			continue
+		}
+		fs = append(fs, f)
+	}
+	return fs
+}
+
+// Group moves the columns of the element fields into a column of a struct named name.
+// If the column already exists, the fields are appended to it. A field which isn't an ungrouped column is an error.
+func (s *Struct) Group(name string, fields ...string) error {
+	var (
+		fs      []Field
+		members []Field
+		at      = -1
+	)
+	for _, f := range s.Fields {
+		switch {
+		case len(f.Fields) > 0 && f.Name == name:
+			members = append(members, f.Fields...)
+		case len(f.Fields) == 0 && slices.Contains(fields, f.Path):
+			members = append(members, f)
+		default:
+			fs = append(fs, f)
+			continue
+		}
+		if at < 0 {
+			at = len(fs)
+			fs = append(fs, Field{})
+		}
+	}
+	for _, p := range fields {
+		if slices.ContainsFunc(members, func(f Field) bool { return f.Path == p }) {
+			continue
+		}
+		for _, f := range s.Fields {
+			if slices.ContainsFunc(f.Fields, func(f Field) bool { return f.Path == p }) {
+				return fmt.Errorf("field %s is already in group %s", p, f.Name)
+			}
+		}
+		return fmt.Errorf("unknown field %s", p)
+	}
+	if at < 0 {
+		return nil
+	}
+	fs[at] = Field{Name: name, Fields: members}
+	if err := checkColumns(fs); err != nil {
+		return err
+	}
+	// If the SoA slice isn't named yet, the types of the groups are checked when it's named.
+	if s.SliceName != "" {
+		if err := (Struct{SliceName: s.SliceName, Fields: fs}).checkTypes(); err != nil {
+			return err
+		}
+	}
+	s.Fields = fs
+	return nil
+}
+
+func (s Struct) args() string {
+	var as []string
+	for _, p := range s.TypeParams {
+		as = append(as, p.Names...)
+	}
+	if len(as) == 0 {
+		return ""
+	}
+	return "[" + strings.Join(as, ", ") + "]"
+}
+
+type TypeParam struct {
+	Names      []string
+	Constraint string
+}
+
+// Field is a column of an SoA slice.
 type Field struct {
-	Names []string
-	Type  string
+	// Name is the name of the column.
+	Name string
+	// Type is the element type of the column.
+	Type string
+	// Path is the field of the element stored in the column.
+	Path string
+	// Fields are the fields of the element grouped into the column. If any, the column is a slice of a struct of them.
+	Fields []Field
+	// DB is the name in the `db` struct tag of the field, if any.
+	DB string
+}
+
+// DBName returns the name of the field in the rows of database/sql. i.e. user_id for `db:"user_id"` or the column name
+// if the field doesn't have the tag. "-" means the field is not scanned.
+func (f Field) DBName() string {
+	if f.DB != "" {
+		return f.DB
+	}
+	return f.Name
+}
+
+// Exported reports whether the column is exported. The default template generates the accessors only for exported
+// columns.
+func (f Field) Exported() bool {
+	return token.IsExported(f.Name)
+}
+
+// Getter returns the name of the method which gets an element of the column. i.e. GetX or getDeleted
+func (f Field) Getter() string {
+	return accessor("Get", f.Name, "")
+}
+
+// Setter returns the name of the method which sets an element of the column. i.e. SetX or setDeleted
+func (f Field) Setter() string {
+	return accessor("Set", f.Name, "")
+}
+
+// Seq returns the name of the method which iterates over the column. i.e. XSeq or deletedSeq
+func (f Field) Seq() string {
+	return accessor("", f.Name, "Seq")
+}
+
+// methods are the names of the methods of an SoA slice other than the accessors of the columns.
+var methods = []string{
+	"Get", "Set", "Len", "Cap", "Slice", "Grow", "Swap", "CopyWithin", "Columns", "Ref", "Refs", "ToSlice",
+	"MarshalJSON", "UnmarshalJSON", "MarshalBinary", "UnmarshalBinary", "WriteTo", "ReadFrom", "WriteArrow", "ReadArrow",
+	"ScanRows",
+}
+
+// checkColumns checks if the column names are unique and don't conflict with the methods.
+func checkColumns(fs []Field) error {
+	seen := make(map[string]bool, len(fs))
+	for _, f := range fs {
+		if seen[f.Name] {
+			return fmt.Errorf("duplicate column %s", f.Name)
+		}
+		seen[f.Name] = true
+	}
+	ms := make(map[string]bool, len(methods)+3*len(fs))
+	for _, m := range methods {
+		ms[m] = true
+	}
+	for _, f := range fs {
+		if !f.Exported() {
+			continue
+		}
+		ms[f.Getter()] = true
+		ms[f.Setter()] = true
+		ms[f.Seq()] = true
+	}
+	for _, f := range fs {
+		if ms[f.Name] {
+			return fmt.Errorf("column %s conflicts with method %[1]s", f.Name)
+		}
+	}
+	return nil
 }
 
-func ParseFile(path string, target ...string) (File, error) {
+func ParseFile(path string, c Config, target ...string) (File, error) {
 	v := visitor{
+		Config:  c,
 		Target:  target,
 		FileSet: token.NewFileSet(),
+		Specs:   map[string]*ast.TypeSpec{},
 	}
 	file, err := parser.ParseFile(v.FileSet, path, nil, parser.SkipObjectResolution)
 	if err != nil {
 		return File{}, err
 	}
 
+	// Nested structs can be declared anywhere in the file.
+	for _, d := range file.Decls {
+		if d, ok := d.(*ast.GenDecl); ok && d.Tok == token.TYPE {
+			for _, spec := range d.Specs {
+				spec := spec.(*ast.TypeSpec)
+				v.Specs[spec.Name.Name] = spec
+			}
+		}
+	}
+
+	renameImports(file)
 	ast.Walk(&v, file)
+	for _, t := range v.Target {
+		if !slices.Contains(v.found, t) {
+			v.errs = append(v.errs, &Error{Err: fmt.Errorf("type %s not found", t)})
+		}
+	}
+	if len(v.errs) > 0 {
+		return File{Warnings: v.Warnings}, errors.Join(v.errs...)
+	}
 	return v.File, nil
 }
 
+// renameImports renames the imports of the file which conflict with the packages the default templates may import along
+// with the references to them.
+func renameImports(file *ast.File) {
+	taken := map[string]bool{}
+	for _, i := range file.Imports {
+		taken[importName(i)] = true
+	}
+	for _, d := range file.Decls {
+		switch d := d.(type) {
+		case *ast.FuncDecl:
+			if d.Recv == nil {
+				taken[d.Name.Name] = true
+			}
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch spec := spec.(type) {
+				case *ast.TypeSpec:
+					taken[spec.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, n := range spec.Names {
+						taken[n.Name] = true
+					}
+				}
+			}
+		}
+	}
+
+	for _, i := range file.Imports {
+		old := importName(i)
+		p, _ := strconv.Unquote(i.Path.Value)
+		if old == "_" || old == "." || !reserved(old, p) {
+			continue
+		}
+		name := old
+		for n := 2; taken[name] || reserved(name, p); n++ {
+			name = old + strconv.Itoa(n)
+		}
+		taken[name] = true
+		i.Name = ast.NewIdent(name)
+		ast.Inspect(file, func(n ast.Node) bool {
+			if s, ok := n.(*ast.SelectorExpr); ok {
+				if x, ok := s.X.(*ast.Ident); ok && x.Name == old {
+					x.Name = name
+				}
+			}
+			return true
+		})
+	}
+}
+
+// importName returns the name of the imported package assuming it's the last element of the path unless it's named.
+func importName(i *ast.ImportSpec) string {
+	if i.Name != nil {
+		return i.Name.Name
+	}
+	p, _ := strconv.Unquote(i.Path.Value)
+	return path.Base(p)
+}
+
 type visitor struct {
 	File
+	Config
 
 	Target  []string
 	FileSet *token.FileSet
+	Specs   map[string]*ast.TypeSpec
+
+	// found are the targets found in the file.
+	found []string
+	errs  []error
 }
 
 func (v *visitor) Visit(n ast.Node) ast.Visitor {
@@ -94,32 +706,160 @@ func (v *visitor) Visit(n ast.Node) ast.Visitor {
 		if len(v.Target) > 0 && !slices.Contains(v.Target, n.Name.Name) {
 			return v
 		}
+		if len(v.Target) > 0 {
+			v.found = append(v.found, n.Name.Name)
+		}
 
 		t, ok := n.Type.(*ast.StructType)
 		if !ok {
+			if len(v.Target) > 0 {
+				v.errs = append(v.errs, errorf(v.FileSet.Position(n.Name.Pos()), "type %s is not a struct", n.Name.Name))
+			}
 			return nil
 		}
 
-		fs := make([]Field, len(t.Fields.List))
-		for i, f := range t.Fields.List {
-			ns := make([]string, len(f.Names))
-			for j, name := range f.Names {
-				ns[j] = name.String()
-			}
-			var buf strings.Builder
-			_ = printer.Fprint(&buf, v.FileSet, f.Type)
-			fs[i] = Field{
-				Names: ns,
-				Type:  buf.String(),
+		var tps []TypeParam
+		if n.TypeParams != nil {
+			tps = make([]TypeParam, len(n.TypeParams.List))
+			for i, p := range n.TypeParams.List {
+				ns := make([]string, len(p.Names))
+				for j, name := range p.Names {
+					ns[j] = name.String()
+				}
+				var buf strings.Builder
+				_ = printer.Fprint(&buf, v.FileSet, p.Type)
+				tps[i] = TypeParam{
+					Names:      ns,
+					Constraint: buf.String(),
+				}
 			}
 		}
 
-		v.Structs = append(v.Structs, Struct{
-			Name:   n.Name.Name,
-			Fields: fs,
-		})
+		st := Struct{
+			Name:       n.Name.Name,
+			TypeParams: tps,
+		}
+		c := columnizer{Config: v.Config}
+		err := c.fields(&st, v.members(t.Fields), v.FileSet.Position(n.Pos()))
+		v.Warnings = append(v.Warnings, c.warnings...)
+		if err != nil {
+			v.errs = append(v.errs, err)
+			return nil
+		}
+		v.Structs = append(v.Structs, st)
 		return nil
 	default:
 		return v
 	}
 }
+
+// members returns the members of a struct type.
+func (v *visitor) members(fl *ast.FieldList) []member {
+	var ms []member
+	for _, f := range fl.List {
+		ns := make([]string, len(f.Names))
+		for j, name := range f.Names {
+			ns[j] = name.String()
+		}
+		if len(ns) == 0 {
+			// An embedded field is named after its type.
+			ns = []string{embeddedName(f.Type)}
+		}
+		var tg string
+		if f.Tag != nil {
+			// The parser guarantees that the tag is a valid string literal.
+			tg, _ = strconv.Unquote(f.Tag.Value)
+		}
+		for _, n := range ns {
+			m := v.member(n, f.Type, f.Pos())
+			m.Tag = reflect.StructTag(tg)
+			m.Embedded = len(f.Names) == 0
+			ms = append(ms, m)
+		}
+	}
+	return ms
+}
+
+// member returns a member of type t.
+func (v *visitor) member(name string, t ast.Expr, pos token.Pos) member {
+	return member{
+		Name: name,
+		Pos:  v.FileSet.Position(pos),
+		Type: func() (string, error) {
+			var buf strings.Builder
+			_ = printer.Fprint(&buf, v.FileSet, t)
+			return buf.String(), nil
+		},
+		Members: func() ([]member, bool) {
+			st, ok := v.resolve(t).(*ast.StructType)
+			if !ok {
+				return nil, false
+			}
+			return v.members(st.Fields), true
+		},
+		Elems: func() ([]member, bool) {
+			a, ok := v.resolve(t).(*ast.ArrayType)
+			if !ok {
+				return nil, false
+			}
+			// The length of an array has to be a literal without type information.
+			l, ok := a.Len.(*ast.BasicLit)
+			if !ok || l.Kind != token.INT {
+				return nil, false
+			}
+			n, err := strconv.ParseInt(l.Value, 0, 0)
+			if err != nil {
+				return nil, false
+			}
+			ms := make([]member, n)
+			for i := range ms {
+				ms[i] = v.member(strconv.Itoa(i), a.Elt, a.Elt.Pos())
+				ms[i].Index = true
+			}
+			return ms, true
+		},
+		Interface: func() bool {
+			_, ok := v.resolve(t).(*ast.InterfaceType)
+			return ok
+		},
+	}
+}
+
+// resolve returns the type expression of t. Only non-generic types declared in the file can be resolved without type
+// information.
+func (v *visitor) resolve(t ast.Expr) ast.Expr {
+	// Invalid declarations may refer to each other. i.e. type A B; type B A
+	for range len(v.Specs) + 1 {
+		i, ok := t.(*ast.Ident)
+		if !ok {
+			return t
+		}
+		spec, ok := v.Specs[i.Name]
+		if !ok || spec.TypeParams != nil {
+			return t
+		}
+		t = spec.Type
+	}
+	return t
+}
+
+// embeddedName returns the implicit field name of an embedded field of type e.
+// i.e. T, *T, pkg.T, T[A], and *pkg.T[A, B] are all named T.
+func embeddedName(e ast.Expr) string {
+	switch e := e.(type) {
+	case *ast.Ident:
+		return e.Name
+	case *ast.StarExpr:
+		return embeddedName(e.X)
+	case *ast.SelectorExpr:
+		return e.Sel.Name
+	case *ast.IndexExpr:
+		return embeddedName(e.X)
+	case *ast.IndexListExpr:
+		return embeddedName(e.X)
+	case *ast.ParenExpr:
+		return embeddedName(e.X)
+	default:
+		return ""
+	}
+}
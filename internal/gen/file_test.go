@@ -1,6 +1,9 @@
 package gen
 
 import (
+	"errors"
+	"fmt"
+	"go/token"
 	"reflect"
 	"strings"
 	"testing"
@@ -11,6 +14,7 @@ func TestParseFile(t *testing.T) {
 		title  string
 		path   string
 		target []string
+		config Config
 		file   File
 		err    bool
 	}{
@@ -21,19 +25,19 @@ func TestParseFile(t *testing.T) {
 			},
 			Structs: []Struct{
 				{Name: "Foo", Fields: []Field{
-					{Names: []string{"ID"}, Type: "int"},
-					{Names: []string{"CreatedAt"}, Type: "t.Time"},
-					{Names: []string{"UpdatedAt"}, Type: "t.Time"},
+					{Name: "ID", Type: "int", Path: "ID"},
+					{Name: "CreatedAt", Type: "t.Time", Path: "CreatedAt"},
+					{Name: "UpdatedAt", Type: "t.Time", Path: "UpdatedAt"},
 				}},
 				{Name: "Bar", Fields: []Field{
-					{Names: []string{"ID"}, Type: "int"},
-					{Names: []string{"CreatedAt"}, Type: "t.Time"},
-					{Names: []string{"UpdatedAt"}, Type: "t.Time"},
+					{Name: "ID", Type: "int", Path: "ID"},
+					{Name: "CreatedAt", Type: "t.Time", Path: "CreatedAt"},
+					{Name: "UpdatedAt", Type: "t.Time", Path: "UpdatedAt"},
 				}},
 				{Name: "Baz", Fields: []Field{
-					{Names: []string{"ID"}, Type: "int"},
-					{Names: []string{"CreatedAt"}, Type: "t.Time"},
-					{Names: []string{"UpdatedAt"}, Type: "t.Time"},
+					{Name: "ID", Type: "int", Path: "ID"},
+					{Name: "CreatedAt", Type: "t.Time", Path: "CreatedAt"},
+					{Name: "UpdatedAt", Type: "t.Time", Path: "UpdatedAt"},
 				}},
 			},
 		}},
@@ -44,17 +48,208 @@ func TestParseFile(t *testing.T) {
 			},
 			Structs: []Struct{
 				{Name: "Foo", Fields: []Field{
-					{Names: []string{"ID"}, Type: "int"},
-					{Names: []string{"CreatedAt"}, Type: "t.Time"},
-					{Names: []string{"UpdatedAt"}, Type: "t.Time"},
+					{Name: "ID", Type: "int", Path: "ID"},
+					{Name: "CreatedAt", Type: "t.Time", Path: "CreatedAt"},
+					{Name: "UpdatedAt", Type: "t.Time", Path: "UpdatedAt"},
 				}},
 				{Name: "Baz", Fields: []Field{
-					{Names: []string{"ID"}, Type: "int"},
-					{Names: []string{"CreatedAt"}, Type: "t.Time"},
-					{Names: []string{"UpdatedAt"}, Type: "t.Time"},
+					{Name: "ID", Type: "int", Path: "ID"},
+					{Name: "CreatedAt", Type: "t.Time", Path: "CreatedAt"},
+					{Name: "UpdatedAt", Type: "t.Time", Path: "UpdatedAt"},
 				}},
 			},
 		}},
+		{title: "generic", path: "testdata/generic.go", file: File{
+			PackageName: "testdata",
+			Structs: []Struct{
+				{Name: "Pair", TypeParams: []TypeParam{
+					{Names: []string{"K"}, Constraint: "comparable"},
+					{Names: []string{"V"}, Constraint: "any"},
+				}, Fields: []Field{
+					{Name: "Key", Type: "K", Path: "Key"},
+					{Name: "Value", Type: "V", Path: "Value"},
+				}},
+				{Name: "Vec", TypeParams: []TypeParam{
+					{Names: []string{"T"}, Constraint: "Number"},
+				}, Fields: []Field{
+					{Name: "X", Type: "T", Path: "X"},
+					{Name: "Y", Type: "T", Path: "Y"},
+				}},
+			},
+		}},
+		{title: "tags", path: "testdata/tags.go", target: []string{"Tagged"}, file: File{
+			PackageName: "testdata",
+			Structs: []Struct{
+				{Name: "Tagged", Fields: []Field{
+					{Name: "ID", Type: "int", Path: "ID", DB: "id"},
+					{Name: "Names", Type: "string", Path: "Name"},
+					{Name: "Score", Type: "float64", Path: "Score"},
+				}},
+			},
+		}},
+		{title: "unknown option", path: "testdata/tags.go", target: []string{"UnknownOption"}, err: true},
+		{title: "duplicate column", path: "testdata/tags.go", target: []string{"DuplicateColumn"}, err: true},
+		{title: "method conflict", path: "testdata/tags.go", target: []string{"MethodConflict"}, err: true},
+		{title: "column name for multiple fields", path: "testdata/tags.go", target: []string{"MultipleNames"}, err: true},
+		{title: "groups", path: "testdata/groups.go", target: []string{"Body"}, file: File{
+			PackageName: "testdata",
+			Structs: []Struct{
+				{Name: "Body", Fields: []Field{
+					{Name: "Hot", Fields: []Field{
+						{Name: "X", Type: "float64", Path: "X"},
+						{Name: "Y", Type: "float64", Path: "Y"},
+						{Name: "VX", Type: "float64", Path: "VX"},
+						{Name: "VY", Type: "float64", Path: "VY"},
+					}},
+					{Name: "Cold", Fields: []Field{
+						{Name: "Name", Type: "string", Path: "Name"},
+						{Name: "Notes", Type: "[]string", Path: "Comments"},
+					}},
+					{Name: "Mass", Type: "float64", Path: "Mass"},
+				}},
+			},
+		}},
+		{title: "package of the same name as the template's", path: "testdata/models/document.go", target: []string{"Document"}, file: File{
+			PackageName: "models",
+			Imports: []Import{
+				{Name: "json2", Path: `"github.com/ichiban/soa/internal/gen/testdata/models/json"`},
+			},
+			Structs: []Struct{
+				{Name: "Document", Fields: []Field{
+					{Name: "Title", Type: "string", Path: "Title"},
+					{Name: "Body", Type: "json2.Raw", Path: "Body"},
+				}},
+			},
+		}},
+		{title: "embedded", path: "testdata/embedded.go", target: []string{"Embedded", "EmbeddedGeneric"}, file: File{
+			PackageName: "testdata",
+			Imports: []Import{
+				{Path: `"time"`},
+			},
+			Structs: []Struct{
+				{Name: "Embedded", Fields: []Field{
+					{Name: "Base", Type: "Base", Path: "Base"},
+					{Name: "Meta", Type: "*Meta", Path: "Meta"},
+					{Name: "Time", Type: "time.Time", Path: "Time"},
+					{Name: "Name", Type: "string", Path: "Name"},
+				}},
+				{Name: "EmbeddedGeneric", Fields: []Field{
+					{Name: "Box", Type: "Box[int]", Path: "Box"},
+					{Name: "Pair", Type: "*Pair[string, int]", Path: "Pair"},
+				}},
+			},
+		}},
+		{title: "flatten", path: "testdata/flatten.go", target: []string{"Particle", "Body3"}, file: File{
+			PackageName: "testdata",
+			Imports: []Import{
+				{Path: `"time"`},
+			},
+			Structs: []Struct{
+				{Name: "Particle", Fields: []Field{
+					{Name: "PosX", Type: "float64", Path: "Pos.X"},
+					{Name: "PosY", Type: "float64", Path: "Pos.Y"},
+					{Name: "PosZ", Type: "float64", Path: "Pos.Z"},
+					{Name: "VX", Type: "float64", Path: "Vel.X"},
+					{Name: "VY", Type: "float64", Path: "Vel.Y"},
+					{Name: "VZ", Type: "float64", Path: "Vel.Z"},
+					{Name: "BoundsMinX", Type: "float64", Path: "Bounds.Min.X"},
+					{Name: "BoundsMinY", Type: "float64", Path: "Bounds.Min.Y"},
+					{Name: "BoundsMinZ", Type: "float64", Path: "Bounds.Min.Z"},
+					{Name: "BoundsMaxX", Type: "float64", Path: "Bounds.Max.X"},
+					{Name: "BoundsMaxY", Type: "float64", Path: "Bounds.Max.Y"},
+					{Name: "BoundsMaxZ", Type: "float64", Path: "Bounds.Max.Z"},
+					{Name: "Mass", Type: "float64", Path: "Mass"},
+					{Name: "Born", Type: "time.Time", Path: "Born"},
+					{Name: "Label", Type: "struct {\n\tText\tstring\n\tColor\tVec3\t`soa:\",column\"`\n}", Path: "Label"},
+				}},
+				{Name: "Body3", Fields: []Field{
+					{Name: "Base", Type: "Base", Path: "Base"},
+					{Name: "X", Type: "float64", Path: "Vec3.X"},
+					{Name: "Y", Type: "float64", Path: "Vec3.Y"},
+					{Name: "Z", Type: "float64", Path: "Vec3.Z"},
+					{Name: "Mass", Type: "float64", Path: "Mass"},
+				}},
+			},
+		}},
+		{title: "flatten all", path: "testdata/flatten.go", target: []string{"Particle"}, config: Config{Flatten: true}, file: File{
+			PackageName: "testdata",
+			Imports: []Import{
+				{Path: `"time"`},
+			},
+			Structs: []Struct{
+				{Name: "Particle", Fields: []Field{
+					{Name: "PosX", Type: "float64", Path: "Pos.X"},
+					{Name: "PosY", Type: "float64", Path: "Pos.Y"},
+					{Name: "PosZ", Type: "float64", Path: "Pos.Z"},
+					{Name: "VX", Type: "float64", Path: "Vel.X"},
+					{Name: "VY", Type: "float64", Path: "Vel.Y"},
+					{Name: "VZ", Type: "float64", Path: "Vel.Z"},
+					{Name: "BoundsMinX", Type: "float64", Path: "Bounds.Min.X"},
+					{Name: "BoundsMinY", Type: "float64", Path: "Bounds.Min.Y"},
+					{Name: "BoundsMinZ", Type: "float64", Path: "Bounds.Min.Z"},
+					{Name: "BoundsMaxX", Type: "float64", Path: "Bounds.Max.X"},
+					{Name: "BoundsMaxY", Type: "float64", Path: "Bounds.Max.Y"},
+					{Name: "BoundsMaxZ", Type: "float64", Path: "Bounds.Max.Z"},
+					{Name: "Mass", Type: "float64", Path: "Mass"},
+					{Name: "Born", Type: "time.Time", Path: "Born"},
+					{Name: "LabelText", Type: "string", Path: "Label.Text"},
+					{Name: "LabelColor", Type: "Vec3", Path: "Label.Color"},
+				}},
+			},
+		}},
+		{title: "flatten non struct", path: "testdata/flatten.go", target: []string{"NotStruct"}, err: true},
+		{title: "split", path: "testdata/split.go", target: []string{"Vertex"}, file: File{
+			PackageName: "testdata",
+			Structs: []Struct{
+				{Name: "Vertex", Fields: []Field{
+					{Name: "Pos0", Type: "float32", Path: "Pos[0]"},
+					{Name: "Pos1", Type: "float32", Path: "Pos[1]"},
+					{Name: "Pos2", Type: "float32", Path: "Pos[2]"},
+					{Name: "C0", Type: "uint8", Path: "Color[0]"},
+					{Name: "C1", Type: "uint8", Path: "Color[1]"},
+					{Name: "C2", Type: "uint8", Path: "Color[2]"},
+					{Name: "C3", Type: "uint8", Path: "Color[3]"},
+					{Name: "UV", Type: "[size]float32", Path: "UV"},
+					{Name: "Normal", Type: "[3]float32", Path: "Normal"},
+					{Name: "Bones0", Type: "Vec3", Path: "Bones[0]"},
+					{Name: "Bones1", Type: "Vec3", Path: "Bones[1]"},
+				}},
+			},
+		}},
+		{title: "split all", path: "testdata/split.go", target: []string{"Vertex"}, config: Config{Split: true}, file: File{
+			PackageName: "testdata",
+			Structs: []Struct{
+				{Name: "Vertex", Fields: []Field{
+					{Name: "Pos0", Type: "float32", Path: "Pos[0]"},
+					{Name: "Pos1", Type: "float32", Path: "Pos[1]"},
+					{Name: "Pos2", Type: "float32", Path: "Pos[2]"},
+					{Name: "C0", Type: "uint8", Path: "Color[0]"},
+					{Name: "C1", Type: "uint8", Path: "Color[1]"},
+					{Name: "C2", Type: "uint8", Path: "Color[2]"},
+					{Name: "C3", Type: "uint8", Path: "Color[3]"},
+					{Name: "UV", Type: "[size]float32", Path: "UV"},
+					{Name: "Normal0", Type: "float32", Path: "Normal[0]"},
+					{Name: "Normal1", Type: "float32", Path: "Normal[1]"},
+					{Name: "Normal2", Type: "float32", Path: "Normal[2]"},
+					{Name: "Bones0", Type: "Vec3", Path: "Bones[0]"},
+					{Name: "Bones1", Type: "Vec3", Path: "Bones[1]"},
+				}},
+			},
+		}},
+		{title: "split large", path: "testdata/split.go", target: []string{"Large"}, config: Config{Split: true}, file: File{
+			PackageName: "testdata",
+			Structs: []Struct{
+				{Name: "Large", Fields: append(elems("Small", "float32", 16), elems("Tagged", "float32", 17)...)},
+			},
+		}},
+		{title: "split too large", path: "testdata/split.go", target: []string{"TooLarge"}, config: Config{Split: true}, file: File{
+			PackageName: "testdata",
+			Structs: []Struct{
+				{Name: "TooLarge", Fields: []Field{{Name: "Matrix", Type: "[17]float32", Path: "Matrix"}}},
+			},
+			Warnings: []*Error{{Pos: token.Position{Filename: "testdata/split.go", Offset: 377, Line: 25, Column: 2}, Err: errors.New("array field Matrix of 17 elements is not split without the split option")}},
+		}},
+		{title: "split non array", path: "testdata/split.go", target: []string{"NotArray"}, err: true},
 		{
 			title: "non Go file",
 			path:  "testdata/test.txt",
@@ -64,7 +259,7 @@ func TestParseFile(t *testing.T) {
 
 	for _, test := range tests {
 		t.Run(test.title, func(t *testing.T) {
-			f, err := ParseFile(test.path, test.target...)
+			f, err := ParseFile(test.path, test.config, test.target...)
 			if test.err != (err != nil) {
 				t.Errorf("error: %v", err)
 			}
@@ -75,12 +270,498 @@ func TestParseFile(t *testing.T) {
 	}
 }
 
+func TestStruct_Type(t *testing.T) {
+	tests := []struct {
+		title     string
+		s         Struct
+		typ       string
+		sliceType string
+		refType   string
+		params    string
+	}{
+		{title: "non generic", s: Struct{Name: "Point", SliceName: "PointSlice"}, typ: "Point", sliceType: "PointSlice", refType: "PointSliceRef"},
+		{title: "slice named after Ref", s: Struct{Name: "Point", SliceName: "PointRef"}, typ: "Point", sliceType: "PointRef", refType: "PointRefRef"},
+		{title: "another package", s: Struct{Name: "Point", Package: "geo", SliceName: "PointSlice"}, typ: "geo.Point", sliceType: "PointSlice", refType: "PointSliceRef"},
+		{title: "generic", s: Struct{Name: "Pair", SliceName: "PairSlice", TypeParams: []TypeParam{
+			{Names: []string{"K"}, Constraint: "comparable"},
+			{Names: []string{"V", "W"}, Constraint: "~int | ~string"},
+		}}, typ: "Pair[K, V, W]", sliceType: "PairSlice[K, V, W]", refType: "PairSliceRef[K, V, W]", params: "[K comparable, V, W ~int | ~string]"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			if got := test.s.Type(); got != test.typ {
+				t.Errorf("got %v, want %v", got, test.typ)
+			}
+			if got := test.s.SliceType(); got != test.sliceType {
+				t.Errorf("got %v, want %v", got, test.sliceType)
+			}
+			if got := test.s.RefType(); got != test.refType {
+				t.Errorf("got %v, want %v", got, test.refType)
+			}
+			if got := test.s.Params(); got != test.params {
+				t.Errorf("got %v, want %v", got, test.params)
+			}
+		})
+	}
+}
+
+func TestStruct_Group(t *testing.T) {
+	point := Struct{Name: "Point", SliceName: "PointSlice", Fields: []Field{
+		{Name: "X", Type: "int", Path: "X"},
+		{Name: "Y", Type: "int", Path: "Y"},
+		{Name: "Label", Type: "string", Path: "Label"},
+	}}
+
+	tests := []struct {
+		title  string
+		s      Struct
+		name   string
+		fields []string
+		result []Field
+		err    bool
+	}{
+		{title: "no fields", s: point, name: "Hot", result: point.Fields},
+		{title: "unknown field", s: point, name: "Hot", fields: []string{"X", "Z"}, result: point.Fields, err: true},
+		{title: "group", s: point, name: "Hot", fields: []string{"Y", "X"}, result: []Field{
+			{Name: "Hot", Fields: []Field{
+				{Name: "X", Type: "int", Path: "X"},
+				{Name: "Y", Type: "int", Path: "Y"},
+			}},
+			{Name: "Label", Type: "string", Path: "Label"},
+		}},
+		{title: "append to existing group", s: Struct{Name: "Point", Fields: []Field{
+			{Name: "Hot", Fields: []Field{
+				{Name: "X", Type: "int", Path: "X"},
+			}},
+			{Name: "Y", Type: "int", Path: "Y"},
+		}}, name: "Hot", fields: []string{"Y"}, result: []Field{
+			{Name: "Hot", Fields: []Field{
+				{Name: "X", Type: "int", Path: "X"},
+				{Name: "Y", Type: "int", Path: "Y"},
+			}},
+		}},
+		{title: "field in another group", s: Struct{Name: "Point", Fields: []Field{
+			{Name: "Hot", Fields: []Field{
+				{Name: "X", Type: "int", Path: "X"},
+			}},
+			{Name: "Y", Type: "int", Path: "Y"},
+		}}, name: "Cold", fields: []string{"X", "Y"}, result: []Field{
+			{Name: "Hot", Fields: []Field{
+				{Name: "X", Type: "int", Path: "X"},
+			}},
+			{Name: "Y", Type: "int", Path: "Y"},
+		}, err: true},
+		{title: "field in the group", s: Struct{Name: "Point", Fields: []Field{
+			{Name: "Hot", Fields: []Field{
+				{Name: "X", Type: "int", Path: "X"},
+			}},
+			{Name: "Y", Type: "int", Path: "Y"},
+		}}, name: "Hot", fields: []string{"X", "Y"}, result: []Field{
+			{Name: "Hot", Fields: []Field{
+				{Name: "X", Type: "int", Path: "X"},
+				{Name: "Y", Type: "int", Path: "Y"},
+			}},
+		}},
+		{title: "conflict", s: point, name: "Label", fields: []string{"X"}, result: point.Fields, err: true},
+		{title: "conflict with Ref", s: point, name: "ref", fields: []string{"X"}, result: point.Fields, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			s := test.s
+			err := s.Group(test.name, test.fields...)
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+			if !reflect.DeepEqual(s.Fields, test.result) {
+				t.Errorf("got %v, want %v", s.Fields, test.result)
+			}
+			if got, want := s.GroupName(Field{Name: "hot"}), s.SliceName+"Hot"; got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestField_Accessors(t *testing.T) {
+	tests := []struct {
+		title               string
+		field               Field
+		exported            bool
+		getter, setter, seq string
+	}{
+		{title: "exported", field: Field{Name: "X"}, exported: true, getter: "GetX", setter: "SetX", seq: "XSeq"},
+		{title: "unexported", field: Field{Name: "deleted"}, getter: "getDeleted", setter: "setDeleted", seq: "deletedSeq"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			if got := test.field.Exported(); got != test.exported {
+				t.Errorf("got %v, want %v", got, test.exported)
+			}
+			if got := test.field.Getter(); got != test.getter {
+				t.Errorf("got %v, want %v", got, test.getter)
+			}
+			if got := test.field.Setter(); got != test.setter {
+				t.Errorf("got %v, want %v", got, test.setter)
+			}
+			if got := test.field.Seq(); got != test.seq {
+				t.Errorf("got %v, want %v", got, test.seq)
+			}
+		})
+	}
+}
+
+func TestStruct_SetSliceName(t *testing.T) {
+	tests := []struct {
+		title  string
+		fields []Field
+		name   string
+		err    bool
+	}{
+		{title: "ok", fields: []Field{{Name: "Hot", Fields: []Field{{Name: "X", Type: "int", Path: "X"}}}}, name: "PointSlice"},
+		{title: "group of Ref", fields: []Field{{Name: "ref", Fields: []Field{{Name: "X", Type: "int", Path: "X"}}}}, name: "PointSlice", err: true},
+		{title: "groups of the same type", fields: []Field{
+			{Name: "hot", Fields: []Field{{Name: "X", Type: "int", Path: "X"}}},
+			{Name: "Hot", Fields: []Field{{Name: "Y", Type: "int", Path: "Y"}}},
+		}, name: "PointSlice", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			s := Struct{Name: "Point", Fields: test.fields}
+			err := s.SetSliceName(test.name)
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+			want := test.name
+			if test.err {
+				want = ""
+			}
+			if s.SliceName != want {
+				t.Errorf("got %v, want %v", s.SliceName, want)
+			}
+		})
+	}
+}
+
+func TestStruct_SetBlockSize(t *testing.T) {
+	tests := []struct {
+		title string
+		s     Struct
+		n     int
+		index string
+		err   bool
+	}{
+		{title: "no blocks", s: Struct{Fields: []Field{{Name: "X", Type: "int", Path: "X"}}}, index: "[i]"},
+		{title: "blocks", s: Struct{Fields: []Field{{Name: "X", Type: "int", Path: "X"}}}, n: 8, index: "[(s.off+i)/8][(s.off+i)%8]"},
+		{title: "negative", s: Struct{Fields: []Field{{Name: "X", Type: "int", Path: "X"}}}, n: -1, index: "[i]", err: true},
+		{title: "no columns", n: 8, index: "[i]", err: true},
+		{title: "reserved", s: Struct{Fields: []Field{{Name: "len", Type: "int", Path: "len"}}}, n: 8, index: "[i]", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			s := test.s
+			err := s.SetBlockSize(test.n)
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+			if got := s.Index("i"); got != test.index {
+				t.Errorf("got %v, want %v", got, test.index)
+			}
+		})
+	}
+}
+
+func TestStruct_SetJSON(t *testing.T) {
+	tests := []struct {
+		title string
+		form  string
+		json  string
+		err   bool
+	}{
+		{title: "none"},
+		{title: "columns", form: "columns", json: JSONColumns},
+		{title: "rows", form: "rows", json: JSONRows},
+		{title: "unknown", form: "tables", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			var s Struct
+			err := s.SetJSON(test.form)
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+			if s.JSON != test.json {
+				t.Errorf("got %v, want %v", s.JSON, test.json)
+			}
+		})
+	}
+}
+
+func TestField_DBName(t *testing.T) {
+	tests := []struct {
+		title string
+		field Field
+		name  string
+	}{
+		{title: "column name", field: Field{Name: "UserID"}, name: "UserID"},
+		{title: "tag", field: Field{Name: "UserID", DB: "user_id"}, name: "user_id"},
+		{title: "skip", field: Field{Name: "UserID", DB: "-"}, name: "-"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			if got := test.field.DBName(); got != test.name {
+				t.Errorf("got %v, want %v", got, test.name)
+			}
+		})
+	}
+}
+
+func TestStruct_SetSQL(t *testing.T) {
+	tests := []struct {
+		title  string
+		fields []Field
+		sql    bool
+		err    bool
+	}{
+		{title: "none", fields: []Field{{Name: "ID"}, {Name: "id"}}},
+		{title: "unique", fields: []Field{{Name: "ID", DB: "id"}, {Name: "Name"}}, sql: true},
+		{title: "skipped", fields: []Field{{Name: "A", DB: "-"}, {Name: "B", DB: "-"}}, sql: true},
+		{title: "duplicate", fields: []Field{{Name: "ID", DB: "id"}, {Name: "id"}}, sql: true, err: true},
+		{title: "duplicate in group", fields: []Field{{Name: "x"}, {Name: "Hot", Fields: []Field{{Name: "X", DB: "x"}}}}, sql: true, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			s := Struct{Fields: test.fields}
+			err := s.SetSQL(test.sql)
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+			if s.SQL != (test.sql && !test.err) {
+				t.Errorf("got %v", s.SQL)
+			}
+		})
+	}
+}
+
+func TestStruct_SetBinary(t *testing.T) {
+	tests := []struct {
+		title  string
+		typ    string
+		binary bool
+		err    bool
+	}{
+		{title: "none", typ: "map[string]int"},
+		{title: "int", typ: "int", binary: true},
+		{title: "string", typ: "string", binary: true},
+		{title: "bytes", typ: "[]byte", binary: true},
+		{title: "array", typ: "[2][3]float64", binary: true},
+		{title: "named", typ: "time.Time", binary: true},
+		{title: "generic", typ: "Pair[int, string]", binary: true},
+		{title: "slice", typ: "[]string", binary: true, err: true},
+		{title: "array of strings", typ: "[2]string", binary: true, err: true},
+		{title: "array of ints", typ: "[2]int", binary: true, err: true},
+		{title: "map", typ: "map[string]int", binary: true, err: true},
+		{title: "pointer", typ: "*int", binary: true, err: true},
+		{title: "interface", typ: "any", binary: true, err: true},
+		{title: "struct", typ: "struct{ X int }", binary: true, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			s := Struct{Fields: []Field{{Name: "ID", Type: "int"}, {Name: "Hot", Fields: []Field{{Name: "X", Type: test.typ}}}}}
+			err := s.SetBinary(test.binary)
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+			if s.Binary != (test.binary && !test.err) {
+				t.Errorf("got %v", s.Binary)
+			}
+		})
+	}
+}
+
+func TestStruct_SetArrow(t *testing.T) {
+	tests := []struct {
+		title string
+		typ   string
+		arrow bool
+		err   bool
+	}{
+		{title: "none", typ: "[]int"},
+		{title: "int", typ: "int", arrow: true},
+		{title: "string", typ: "string", arrow: true},
+		{title: "named", typ: "time.Time", arrow: true},
+		{title: "bytes", typ: "[]byte", arrow: true, err: true},
+		{title: "array", typ: "[2]float64", arrow: true, err: true},
+		{title: "complex", typ: "complex128", arrow: true, err: true},
+		{title: "map", typ: "map[string]int", arrow: true, err: true},
+		{title: "pointer", typ: "*int", arrow: true, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			s := Struct{Fields: []Field{{Name: "ID", Type: "int"}, {Name: "Hot", Fields: []Field{{Name: "X", Type: test.typ}}}}}
+			err := s.SetArrow(test.arrow)
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+			if s.Arrow != (test.arrow && !test.err) {
+				t.Errorf("got %v", s.Arrow)
+			}
+		})
+	}
+}
+
+func TestStruct_Leaves(t *testing.T) {
+	s := Struct{Fields: []Field{
+		{Name: "ID", Type: "int", Path: "ID"},
+		{Name: "Hot", Fields: []Field{
+			{Name: "X", Type: "float64", Path: "Pos.X"},
+			{Name: "Y", Type: "float64", Path: "Pos.Y"},
+		}},
+		{Name: "deleted", Type: "bool", Path: "deleted"},
+	}}
+	want := []Field{
+		{Name: "ID", Type: "int", Path: "ID"},
+		{Name: "X", Type: "float64", Path: "Pos.X"},
+		{Name: "Y", Type: "float64", Path: "Pos.Y"},
+		{Name: "deleted", Type: "bool", Path: "deleted"},
+	}
+	if got := s.Leaves(); !reflect.DeepEqual(got, want) {
+		t.Errorf("got %v, want %v", got, want)
+	}
+}
+
+func TestFile_StdImports(t *testing.T) {
+	tests := []struct {
+		title string
+		file  File
+		std   []string
+	}{
+		{title: "empty"},
+		{title: "slices", file: File{Structs: []Struct{{}}}, std: []string{"iter", "slices"}},
+		{title: "blocks", file: File{Structs: []Struct{{BlockSize: 8}}}, std: []string{"fmt", "iter"}},
+		{title: "mixed", file: File{Structs: []Struct{{}, {BlockSize: 8}}}, std: []string{"fmt", "iter", "slices"}},
+		{title: "json", file: File{Structs: []Struct{{JSON: JSONRows}}}, std: []string{"encoding/json", "fmt", "iter", "slices"}},
+		{title: "binary", file: File{Structs: []Struct{{Binary: true}}}, std: []string{"bytes", "iter", "io", "slices"}},
+		{title: "arrow", file: File{Structs: []Struct{{Arrow: true}}}, std: []string{"iter", "io", "slices"}},
+		{title: "sql", file: File{Structs: []Struct{{SQL: true}}}, std: []string{"database/sql", "fmt", "iter", "slices"}},
+		{
+			title: "imported",
+			file:  File{Imports: []Import{{Path: `"database/sql"`}}, Structs: []Struct{{SQL: true}}},
+			std:   []string{"fmt", "iter", "slices"},
+		},
+		{
+			title: "imported by another name",
+			file:  File{Imports: []Import{{Name: "sql2", Path: `"database/sql"`}}, Structs: []Struct{{SQL: true}}},
+			std:   []string{"database/sql", "fmt", "iter", "slices"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			if got := test.file.StdImports(); !reflect.DeepEqual(got, test.std) {
+				t.Errorf("got %v, want %v", got, test.std)
+			}
+		})
+	}
+}
+
+func TestFile_StdTestImports(t *testing.T) {
+	tests := []struct {
+		title string
+		file  File
+		std   []string
+	}{
+		{title: "empty", std: []string{"math/rand", "reflect", "testing"}},
+		{title: "json", file: File{Structs: []Struct{{}, {JSON: JSONColumns}}}, std: []string{"encoding/json", "math/rand", "reflect", "testing"}},
+		{title: "binary", file: File{Structs: []Struct{{Binary: true, JSON: JSONRows}}}, std: []string{"bytes", "encoding/json", "math/rand", "reflect", "testing"}},
+		{
+			title: "generic json",
+			file:  File{Structs: []Struct{{JSON: JSONRows, TypeParams: []TypeParam{{Names: []string{"T"}, Constraint: "any"}}}}},
+			std:   []string{"math/rand", "reflect", "testing"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			if got := test.file.StdTestImports(); !reflect.DeepEqual(got, test.std) {
+				t.Errorf("got %v, want %v", got, test.std)
+			}
+		})
+	}
+}
+
+func TestFile_LibImports(t *testing.T) {
+	tests := []struct {
+		title string
+		file  File
+		lib   []string
+	}{
+		{title: "empty"},
+		{title: "no binary", file: File{Structs: []Struct{{}}}},
+		{title: "binary", file: File{Structs: []Struct{{}, {Binary: true}}}, lib: []string{"github.com/ichiban/soa/soabin"}},
+		{title: "arrow", file: File{Structs: []Struct{{Arrow: true}}}, lib: []string{"github.com/ichiban/soa/soaarrow"}},
+		{
+			title: "binary and arrow",
+			file:  File{Structs: []Struct{{Binary: true, Arrow: true}}},
+			lib:   []string{"github.com/ichiban/soa/soabin", "github.com/ichiban/soa/soaarrow"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			if got := test.file.LibImports(); !reflect.DeepEqual(got, test.lib) {
+				t.Errorf("got %v, want %v", got, test.lib)
+			}
+		})
+	}
+}
+
+func TestFile_LibTestImports(t *testing.T) {
+	tests := []struct {
+		title string
+		file  File
+		lib   []string
+	}{
+		{title: "empty", lib: []string{"github.com/ichiban/soa", "github.com/ichiban/soa/soatest"}},
+		{
+			title: "binary",
+			file:  File{Structs: []Struct{{}, {Binary: true}}},
+			lib:   []string{"github.com/ichiban/soa", "github.com/ichiban/soa/soatest", "github.com/ichiban/soa/soabin"},
+		},
+		{
+			title: "generic binary",
+			file:  File{Structs: []Struct{{Binary: true, TypeParams: []TypeParam{{Names: []string{"T"}, Constraint: "any"}}}}},
+			lib:   []string{"github.com/ichiban/soa", "github.com/ichiban/soa/soatest"},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			if got := test.file.LibTestImports(); !reflect.DeepEqual(got, test.lib) {
+				t.Errorf("got %v, want %v", got, test.lib)
+			}
+		})
+	}
+}
+
 func TestFile_WriteTo(t *testing.T) {
 	tests := []struct {
 		title string
 		file  File
 		out   string
-		err   bool
+		// want and notWant are the parts of the output checked instead of the whole output if any.
+		want    []string
+		notWant []string
+		err     bool
 	}{
 		{
 			title: "minimal",
@@ -89,6 +770,39 @@ func TestFile_WriteTo(t *testing.T) {
 package test
 `,
 		},
+		{
+			title: "embedded",
+			file: File{PackageName: "test", Imports: []Import{{Path: `"time"`}}, Structs: []Struct{
+				{Name: "Embedded", SliceName: "EmbeddedSlice", Fields: []Field{
+					{Name: "Base", Type: "Base", Path: "Base"},
+					{Name: "Meta", Type: "*Meta", Path: "Meta"},
+					{Name: "Time", Type: "time.Time", Path: "Time"},
+					{Name: "Box", Type: "Box[int]", Path: "Box"},
+				}},
+			}},
+			want: []string{
+				"Base []Base",
+				"Meta []*Meta",
+				"Time []time.Time",
+				"Box  []Box[int]",
+				"t.Base = s.Base[i]",
+				"t.Meta = s.Meta[i]",
+				"t.Time = s.Time[i]",
+				"t.Box = s.Box[i]",
+				"s.Meta[i] = t.Meta",
+			},
+		},
+		{
+			title: "unexported column",
+			file: File{PackageName: "test", Structs: []Struct{
+				{Name: "User", SliceName: "UserSlice", Fields: []Field{
+					{Name: "ID", Type: "int", Path: "ID"},
+					{Name: "deleted", Type: "bool", Path: "deleted"},
+				}},
+			}},
+			want:    []string{"func (s UserSlice) GetID(i int) int {", "func (s UserSlice) Columns() ([]int, []bool) {"},
+			notWant: []string{"getDeleted", "setDeleted", "deletedSeq"},
+		},
 		{
 			title: "invalid package name",
 			file:  File{PackageName: "123"},
@@ -103,9 +817,141 @@ package test
 			if test.err != (err != nil) {
 				t.Errorf("error: %v", err)
 			}
+			if test.want != nil {
+				for _, s := range test.want {
+					if !strings.Contains(sb.String(), s) {
+						t.Errorf("%q not found in %s", s, sb.String())
+					}
+				}
+				for _, s := range test.notWant {
+					if strings.Contains(sb.String(), s) {
+						t.Errorf("%q found in %s", s, sb.String())
+					}
+				}
+				return
+			}
 			if got, want := sb.String(), test.out; got != want {
 				t.Errorf("got %v, want %v", got, want)
 			}
 		})
 	}
 }
+
+func TestFile_WriteTestTo(t *testing.T) {
+	tests := []struct {
+		title   string
+		file    File
+		want    []string
+		notWant []string
+	}{
+		{
+			title: "struct",
+			file: File{PackageName: "test", Structs: []Struct{
+				{Name: "Point", SliceName: "PointSlice", Fields: []Field{{Name: "X", Type: "int", Path: "X"}}},
+			}},
+			want: []string{
+				"var _ soa.Slice[PointSlice, Point] = PointSlice{}",
+				"func TestPointSlice(t *testing.T) {",
+				"if !reflect.DeepEqual(got.X, want.X) {",
+				"for i, ref := range s.Refs() {",
+				"*ref.X = want.X",
+				"soatest.Run[PointSlice](t, func() Point {",
+			},
+		},
+		{
+			title: "grouped column",
+			file: File{PackageName: "test", Structs: []Struct{
+				{Name: "Point", SliceName: "pointSlice", Fields: []Field{{Name: "Pos", Fields: []Field{
+					{Name: "X", Type: "int", Path: "Pos.X"},
+				}}}},
+			}},
+			want: []string{
+				"func TestPointSlice(t *testing.T) {",
+				"if !reflect.DeepEqual(got.Pos.X, want.Pos.X) {",
+				"ref.Pos.X = want.Pos.X",
+			},
+		},
+		{
+			title: "generic",
+			file: File{PackageName: "test", Structs: []Struct{
+				{Name: "Pair", SliceName: "PairSlice", TypeParams: []TypeParam{{Names: []string{"T"}, Constraint: "any"}}, Fields: []Field{
+					{Name: "A", Type: "T", Path: "A"},
+				}},
+			}},
+			notWant: []string{"PairSlice"},
+		},
+		{
+			title: "json columns",
+			file: File{PackageName: "test", Structs: []Struct{
+				{Name: "Point", SliceName: "PointSlice", JSON: JSONColumns, Fields: []Field{
+					{Name: "X", Type: "int", Path: "X"},
+					{Name: "Y", Type: "int", Path: "Y"},
+				}},
+			}},
+			want: []string{
+				`"encoding/json"`,
+				`t.Run("json", func(t *testing.T) {`,
+				"b, err := json.Marshal(soa.FromSlice[PointSlice](want))",
+				"if err := json.Unmarshal([]byte(`{\"Y\":[null]}`), &s); err == nil || err.Error() != \"column Y has 1 elements, want 0\" {",
+			},
+		},
+		{
+			title: "json columns of a column",
+			file: File{PackageName: "test", Structs: []Struct{
+				{Name: "Point", SliceName: "PointSlice", JSON: JSONColumns, Fields: []Field{{Name: "X", Type: "int", Path: "X"}}},
+			}},
+			want:    []string{`t.Run("json", func(t *testing.T) {`},
+			notWant: []string{"elements, want"},
+		},
+		{
+			title: "json rows",
+			file: File{PackageName: "test", Structs: []Struct{
+				{Name: "Point", SliceName: "PointSlice", JSON: JSONRows, Fields: []Field{{Name: "X", Type: "int", Path: "X"}}},
+			}},
+			want: []string{
+				`t.Run("json", func(t *testing.T) {`,
+				"if err := json.Unmarshal([]byte(`[{}]`), &s); err == nil || err.Error() != \"row 0 has no column X\" {",
+			},
+		},
+		{
+			title: "binary",
+			file: File{PackageName: "test", Structs: []Struct{
+				{Name: "Point", SliceName: "PointSlice", Binary: true, Fields: []Field{{Name: "X", Type: "int", Path: "X"}}},
+			}},
+			want: []string{
+				`t.Run("binary", func(t *testing.T) {`,
+				"b, err := soa.FromSlice[PointSlice](want).MarshalBinary()",
+				`if _, err := soabin.Encode(&w, 1<<30, soabin.Col("X", []int{})); err != nil {`,
+				"if err := s.UnmarshalBinary(w.Bytes()); err == nil {",
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			var sb strings.Builder
+			if _, err := test.file.WriteTestTo(&sb); err != nil {
+				t.Fatal(err)
+			}
+			for _, s := range test.want {
+				if !strings.Contains(sb.String(), s) {
+					t.Errorf("%q not found in %s", s, sb.String())
+				}
+			}
+			for _, s := range test.notWant {
+				if strings.Contains(sb.String(), s) {
+					t.Errorf("%q found in %s", s, sb.String())
+				}
+			}
+		})
+	}
+}
+
+// elems returns the columns of the n elements of the array field name.
+func elems(name, typ string, n int) []Field {
+	fs := make([]Field, n)
+	for i := range fs {
+		fs[i] = Field{Name: fmt.Sprintf("%s%d", name, i), Type: typ, Path: fmt.Sprintf("%s[%d]", name, i)}
+	}
+	return fs
+}
@@ -0,0 +1,170 @@
+package gen
+
+import (
+	"errors"
+	"go/token"
+	"reflect"
+	"slices"
+	"strings"
+)
+
+// Config configures how the fields of structs are stored in columns.
+type Config struct {
+	// Flatten stores nested struct fields in columns of their fields recursively unless a tag specifies otherwise.
+	Flatten bool
+	// Split stores fixed-size array fields of up to MaxSplit elements in columns of their elements unless a tag
+	// specifies otherwise.
+	Split bool
+}
+
+// MaxSplit is the maximum number of elements of an array field split by Config.Split. A larger array has to be tagged
+// with `soa:",split"` to be split.
+const MaxSplit = 16
+
+// member is a field of a struct to be stored in columns.
+type member struct {
+	Name     string
+	Tag      reflect.StructTag
+	Embedded bool
+	// Index is true if the member is an element of an array and Name is the index.
+	Index bool
+	// Pos is the position of the field for error messages.
+	Pos token.Position
+	// Foreign is the path of the package the field is declared in if it's unexported and can't be accessed from the
+	// generated code.
+	Foreign string
+	// Type returns the type of the field.
+	Type func() (string, error)
+	// Members returns the fields of the field type if it's a struct.
+	Members func() ([]member, bool)
+	// Elems returns the elements of the field type if it's an array.
+	Elems func() ([]member, bool)
+	// Interface reports whether the field type is known to be an interface.
+	Interface func() bool
+}
+
+// columnizer converts members of a struct to columns.
+type columnizer struct {
+	Config
+	groups groups
+
+	// stack is the types of the structs being flattened to detect recursion.
+	stack []string
+	// errs are the problems found in the members which prevent the generation.
+	errs []error
+	// warnings are the suspicious members which don't prevent the generation.
+	warnings []*Error
+}
+
+// columns returns the columns for the members. Columns of nested fields are prefixed with the name of the parent column
+// and their paths are relative to path. If flatten is true, nested struct fields are flattened unless a tag specifies
+// otherwise. Likewise, if split is true, array fields are split. Problems are recorded and the members having them are
+// skipped so that all of them are reported at once.
+func (c *columnizer) columns(ms []member, prefix, path string, flatten, split bool) []Field {
+	var fs []Field
+	for _, m := range ms {
+		tg, err := parseTag(m.Tag)
+		if err != nil {
+			c.errs = append(c.errs, &Error{Pos: m.Pos, Err: err})
+			continue
+		}
+		if tg.Skip {
+			continue
+		}
+		if m.Name == "_" {
+			// A blank field can be neither read nor written.
+			c.warnings = append(c.warnings, errorf(m.Pos, "blank field is not stored"))
+			continue
+		}
+		if m.Foreign != "" {
+			c.errs = append(c.errs, errorf(m.Pos, "unexported field %s of package %s cannot be accessed", m.Name, m.Foreign))
+			continue
+		}
+
+		name, p := prefix+m.Name, m.Name
+		if tg.Name != "" {
+			name = prefix + tg.Name
+		}
+		switch {
+		case m.Index:
+			p = path + "[" + m.Name + "]"
+		case path != "":
+			p = path + "." + m.Name
+		}
+
+		if tg.Storage == StorageFlatten || tg.Storage == "" && flatten {
+			ns, ok := m.Members()
+			switch {
+			case ok && (tg.Storage == StorageFlatten || !slices.ContainsFunc(ns, func(m member) bool {
+				return m.Foreign != ""
+			})):
+				t, err := m.Type()
+				if err != nil {
+					c.errs = append(c.errs, &Error{Pos: m.Pos, Err: err})
+					continue
+				}
+				if slices.Contains(c.stack, t) {
+					c.errs = append(c.errs, errorf(m.Pos, "field %s of recursive type %s cannot be flattened", m.Name, t))
+					continue
+				}
+				// Promoted fields of an embedded struct keep their names.
+				if m.Embedded && tg.Name == "" {
+					name = prefix
+				}
+				c.stack = append(c.stack, t)
+				fs = append(fs, c.columns(ns, name, p, true, split)...)
+				c.stack = c.stack[:len(c.stack)-1]
+				continue
+			case tg.Storage == StorageFlatten:
+				c.errs = append(c.errs, errorf(m.Pos, "field %s is not a struct", m.Name))
+				continue
+			}
+		}
+
+		if tg.Storage == StorageSplit || tg.Storage == "" && split {
+			es, ok := m.Elems()
+			switch {
+			case ok && tg.Storage == "" && len(es) > MaxSplit:
+				// Splitting a large array by accident bloats the SoA slice with columns.
+				c.warnings = append(c.warnings, errorf(m.Pos, "array field %s of %d elements is not split without the split option", m.Name, len(es)))
+			case ok:
+				fs = append(fs, c.columns(es, name, p, flatten, true)...)
+				continue
+			case tg.Storage == StorageSplit:
+				c.errs = append(c.errs, errorf(m.Pos, "field %s is not an array of a known length", m.Name))
+				continue
+			}
+		}
+
+		t, err := m.Type()
+		if err != nil {
+			c.errs = append(c.errs, &Error{Pos: m.Pos, Err: err})
+			continue
+		}
+		if m.Embedded && m.Interface() {
+			c.warnings = append(c.warnings, errorf(m.Pos, "methods of embedded interface %s are not promoted to the SoA slice", t))
+		}
+		db, _, _ := strings.Cut(m.Tag.Get("db"), ",")
+		fs = append(fs, Field{Name: name, Type: t, Path: p, DB: db})
+		c.groups.add(tg, p)
+	}
+	return fs
+}
+
+// fields sets the columns for the members of a struct declared at pos. All the problems found in the members are
+// joined into the returned error.
+func (c *columnizer) fields(s *Struct, ms []member, pos token.Position) error {
+	c.stack = []string{s.Type()}
+	fs := c.columns(ms, "", "", c.Flatten, c.Split)
+	if len(c.errs) > 0 {
+		return errors.Join(c.errs...)
+	}
+	if err := checkColumns(fs); err != nil {
+		return &Error{Pos: pos, Err: err}
+	}
+	s.Fields = fs
+	if err := c.groups.apply(s); err != nil {
+		return &Error{Pos: pos, Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,301 @@
+package gen
+
+import (
+	"errors"
+	"fmt"
+	"go/ast"
+	"go/token"
+	"go/types"
+	"path"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"golang.org/x/tools/go/packages"
+)
+
+// LoadPackage loads the package in dir with type information and returns a File containing SoA slices for the structs
+// declared in any file of the package. Unlike ParseFile, the imports of the returned File are exactly the ones the
+// generated code needs.
+//
+// A target can be qualified with an import path, i.e. example.com/models.Order, to generate an SoA slice in the package
+// in dir for a struct declared in another package.
+//
+// Problems in the input are joined into the returned error as *Error so that all of them are reported at once. The
+// returned File has the Warnings even if it fails.
+func LoadPackage(dir string, c Config, target ...string) (File, error) {
+	var (
+		local   []string
+		paths   []string
+		foreign = map[string][]string{}
+	)
+	for _, t := range target {
+		i := strings.LastIndex(t, ".")
+		if i < 0 {
+			local = append(local, t)
+			continue
+		}
+		p, name := t[:i], t[i+1:]
+		if _, ok := foreign[p]; !ok {
+			paths = append(paths, p)
+		}
+		foreign[p] = append(foreign[p], name)
+	}
+
+	abs, err := filepath.Abs(dir)
+	if err != nil {
+		return File{}, err
+	}
+	cfg := packages.Config{
+		// NeedDeps type-checks the dependencies from source so that loading doesn't rely on the export data format of
+		// the installed toolchain.
+		Mode: packages.NeedName | packages.NeedFiles | packages.NeedImports | packages.NeedDeps | packages.NeedTypes | packages.NeedSyntax | packages.NeedTypesInfo,
+		Dir:  abs,
+	}
+	pkgs, err := packages.Load(&cfg, append([]string{"."}, paths...)...)
+	if err != nil {
+		return File{}, err
+	}
+	var pkg *packages.Package
+	byPath := make(map[string]*packages.Package, len(pkgs))
+	for _, p := range pkgs {
+		for _, e := range p.Errors {
+			// The package may not type-check until the SoA slices are (re)generated. We report invalid field types instead.
+			if e.Kind != packages.TypeError {
+				return File{}, e
+			}
+		}
+		byPath[p.PkgPath] = p
+		if len(p.GoFiles) > 0 && filepath.Dir(p.GoFiles[0]) == abs {
+			pkg = p
+		}
+	}
+	if pkg == nil {
+		return File{}, fmt.Errorf("no package found in %s", dir)
+	}
+
+	q := newQualifier(pkg.Types)
+	f := File{PackageName: pkg.Name, exact: true}
+	var (
+		errs  []error
+		found []string
+	)
+	for _, file := range pkg.Syntax {
+		if generatedBySoagen(file) {
+			continue
+		}
+		for _, d := range file.Decls {
+			d, ok := d.(*ast.GenDecl)
+			if !ok || d.Tok != token.TYPE {
+				continue
+			}
+			for _, spec := range d.Specs {
+				spec := spec.(*ast.TypeSpec)
+				if len(target) > 0 && !slices.Contains(local, spec.Name.Name) {
+					continue
+				}
+				found = append(found, spec.Name.Name)
+
+				obj, ok := pkg.TypesInfo.Defs[spec.Name].(*types.TypeName)
+				if !ok {
+					continue
+				}
+				ok, err := addStruct(&f, pkg.Fset, q, c, obj)
+				if err != nil {
+					errs = append(errs, err)
+					continue
+				}
+				if !ok && len(target) > 0 {
+					errs = append(errs, notStruct(pkg.Fset, obj, obj.Name()))
+				}
+			}
+		}
+	}
+	for _, t := range local {
+		if !slices.Contains(found, t) {
+			errs = append(errs, &Error{Err: fmt.Errorf("type %s not found", t)})
+		}
+	}
+	for _, p := range paths {
+		fp, ok := byPath[p]
+		if !ok {
+			return File{}, fmt.Errorf("package %s not found", p)
+		}
+		for _, name := range foreign[p] {
+			obj, ok := fp.Types.Scope().Lookup(name).(*types.TypeName)
+			if !ok {
+				errs = append(errs, &Error{Err: fmt.Errorf("type %s.%s not found", p, name)})
+				continue
+			}
+			if !obj.Exported() {
+				errs = append(errs, errorf(fp.Fset.Position(obj.Pos()), "type %s.%s is not exported", p, name))
+				continue
+			}
+			ok, err := addStruct(&f, fp.Fset, q, c, obj)
+			if err != nil {
+				errs = append(errs, err)
+				continue
+			}
+			if !ok {
+				errs = append(errs, notStruct(fp.Fset, obj, p+"."+name))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return File{Warnings: f.Warnings}, errors.Join(errs...)
+	}
+	f.Imports = q.imports
+	slices.SortFunc(f.Imports, func(a, b Import) int {
+		return strings.Compare(a.Path, b.Path)
+	})
+	return f, nil
+}
+
+// addStruct adds a Struct for the type name to f if its underlying type is a struct. The warnings about its fields are
+// added to f even if it fails.
+func addStruct(f *File, fset *token.FileSet, q *qualifier, cfg Config, obj *types.TypeName) (bool, error) {
+	st, ok := obj.Type().Underlying().(*types.Struct)
+	if !ok {
+		return false, nil
+	}
+
+	s := Struct{Name: obj.Name(), Package: q.qualify(obj.Pkg())}
+	if n, ok := obj.Type().(*types.Named); ok && !obj.IsAlias() {
+		tps := n.TypeParams()
+		for i := 0; i < tps.Len(); i++ {
+			tp := tps.At(i)
+			s.TypeParams = append(s.TypeParams, TypeParam{
+				Names:      []string{tp.Obj().Name()},
+				Constraint: types.TypeString(tp.Constraint(), q.qualify),
+			})
+		}
+	}
+	c := columnizer{Config: cfg}
+	err := c.fields(&s, members(fset, q, st), fset.Position(obj.Pos()))
+	f.Warnings = append(f.Warnings, c.warnings...)
+	if err != nil {
+		return false, err
+	}
+	f.Structs = append(f.Structs, s)
+	return true, nil
+}
+
+// notStruct returns an Error for the target type name which isn't a struct.
+func notStruct(fset *token.FileSet, obj *types.TypeName, name string) *Error {
+	// The type checker invalidates types such as recursive structs. i.e. type A struct { B B }; type B struct { A A }
+	if obj.Type().Underlying() == types.Typ[types.Invalid] {
+		return errorf(fset.Position(obj.Pos()), "type %s is invalid", name)
+	}
+	return errorf(fset.Position(obj.Pos()), "type %s is not a struct", name)
+}
+
+// members returns the members of a struct type.
+func members(fset *token.FileSet, q *qualifier, st *types.Struct) []member {
+	ms := make([]member, st.NumFields())
+	for i := range ms {
+		f := st.Field(i)
+		m := newMember(fset, q, f.Name(), f.Type(), f.Pos())
+		m.Tag = reflect.StructTag(st.Tag(i))
+		m.Embedded = f.Embedded()
+		// Fields of a struct declared in another package have to be accessible from the generated code.
+		if f.Pkg() != q.pkg && !f.Exported() {
+			m.Foreign = f.Pkg().Path()
+		}
+		ms[i] = m
+	}
+	return ms
+}
+
+// newMember returns a member of type t.
+func newMember(fset *token.FileSet, q *qualifier, name string, t types.Type, pos token.Pos) member {
+	return member{
+		Name: name,
+		Pos:  fset.Position(pos),
+		Type: func() (string, error) {
+			s := types.TypeString(t, q.qualify)
+			// An invalid type can be nested anywhere in the field type. i.e. map[string][]Undefined
+			if strings.Contains(s, "invalid type") {
+				return "", fmt.Errorf("invalid type of field %s", name)
+			}
+			return s, nil
+		},
+		Members: func() ([]member, bool) {
+			st, ok := t.Underlying().(*types.Struct)
+			if !ok {
+				return nil, false
+			}
+			return members(fset, q, st), true
+		},
+		Elems: func() ([]member, bool) {
+			a, ok := t.Underlying().(*types.Array)
+			if !ok {
+				return nil, false
+			}
+			ms := make([]member, a.Len())
+			for i := range ms {
+				ms[i] = newMember(fset, q, strconv.Itoa(i), a.Elem(), pos)
+				ms[i].Index = true
+			}
+			return ms, true
+		},
+		Interface: func() bool {
+			// The constraint of a type parameter is an interface but the type parameter itself isn't.
+			_, ok := t.(*types.TypeParam)
+			return !ok && types.IsInterface(t)
+		},
+	}
+}
+
+// generatedBySoagen checks if the file is an output of soagen.
+func generatedBySoagen(f *ast.File) bool {
+	for _, c := range f.Comments {
+		if c.Pos() > f.Package {
+			break
+		}
+		if strings.HasPrefix(c.Text(), "Code generated by soagen") {
+			return true
+		}
+	}
+	return false
+}
+
+// qualifier names the packages referred by the generated code and keeps track of their imports.
+type qualifier struct {
+	pkg     *types.Package
+	names   map[string]string
+	taken   map[string]bool
+	imports []Import
+}
+
+func newQualifier(pkg *types.Package) *qualifier {
+	return &qualifier{
+		pkg:   pkg,
+		names: map[string]string{},
+		taken: map[string]bool{},
+	}
+}
+
+func (q *qualifier) qualify(p *types.Package) string {
+	if p == q.pkg {
+		return ""
+	}
+	if n, ok := q.names[p.Path()]; ok {
+		return n
+	}
+
+	name := p.Name()
+	for i := 2; q.taken[name] || reserved(name, p.Path()) || q.pkg.Scope().Lookup(name) != nil; i++ {
+		name = p.Name() + strconv.Itoa(i)
+	}
+	q.names[p.Path()] = name
+	q.taken[name] = true
+
+	i := Import{Path: strconv.Quote(p.Path())}
+	if name != path.Base(p.Path()) {
+		i.Name = name
+	}
+	q.imports = append(q.imports, i)
+	return name
+}
@@ -0,0 +1,144 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestLoadPackage(t *testing.T) {
+	tests := []struct {
+		title  string
+		dir    string
+		target []string
+		config Config
+		file   File
+		err    bool
+	}{
+		{title: "all", dir: "testdata/pkg", file: File{
+			PackageName: "pkg",
+			exact:       true,
+			Imports: []Import{
+				{Path: `"net/url"`},
+				{Path: `"time"`},
+			},
+			Structs: []Struct{
+				{Name: "Point", Fields: []Field{
+					{Name: "X", Type: "int", Path: "X"},
+					{Name: "Y", Type: "int", Path: "Y"},
+					{Name: "Loc", Type: "Location", Path: "Loc"},
+				}},
+				{Name: "Event", Fields: []Field{
+					{Name: "At", Type: "time.Time", Path: "At"},
+					{Name: "URL", Type: "*url.URL", Path: "URL"},
+					{Name: "Note", Type: "Note", Path: "Note"},
+				}},
+				{Name: "Location", Fields: []Field{
+					{Name: "Name", Type: "string", Path: "Name"},
+				}},
+				{Name: "Coord", Fields: []Field{
+					{Name: "X", Type: "int", Path: "X"},
+					{Name: "Y", Type: "int", Path: "Y"},
+					{Name: "Loc", Type: "Location", Path: "Loc"},
+				}},
+				{Name: "Tagged", TypeParams: []TypeParam{
+					{Names: []string{"T"}, Constraint: "any"},
+				}, Fields: []Field{
+					{Name: "Value", Type: "T", Path: "Value"},
+					{Name: "Tags", Type: "[]string", Path: "Tags"},
+				}},
+				{Name: "Sample", Fields: []Field{
+					{Name: "Values", Type: "[2]float64", Path: "Values"},
+					{Name: "Tags", Type: "[]string", Path: "Tags"},
+				}},
+			},
+		}},
+		{title: "limit to Point", dir: "testdata/pkg", target: []string{"Point"}, file: File{
+			PackageName: "pkg",
+			exact:       true,
+			Structs: []Struct{
+				{Name: "Point", Fields: []Field{
+					{Name: "X", Type: "int", Path: "X"},
+					{Name: "Y", Type: "int", Path: "Y"},
+					{Name: "Loc", Type: "Location", Path: "Loc"},
+				}},
+			},
+		}},
+		{title: "flatten all", dir: "testdata/pkg", target: []string{"Point", "Event"}, config: Config{Flatten: true}, file: File{
+			PackageName: "pkg",
+			exact:       true,
+			Imports: []Import{
+				{Path: `"net/url"`},
+				{Path: `"time"`},
+			},
+			Structs: []Struct{
+				{Name: "Point", Fields: []Field{
+					{Name: "X", Type: "int", Path: "X"},
+					{Name: "Y", Type: "int", Path: "Y"},
+					{Name: "LocName", Type: "string", Path: "Loc.Name"},
+				}},
+				{Name: "Event", Fields: []Field{
+					{Name: "At", Type: "time.Time", Path: "At"},
+					{Name: "URL", Type: "*url.URL", Path: "URL"},
+					{Name: "Note", Type: "Note", Path: "Note"},
+				}},
+			},
+		}},
+		{title: "split all", dir: "testdata/pkg", target: []string{"Sample"}, config: Config{Split: true}, file: File{
+			PackageName: "pkg",
+			exact:       true,
+			Structs: []Struct{
+				{Name: "Sample", Fields: []Field{
+					{Name: "Values0", Type: "float64", Path: "Values[0]"},
+					{Name: "Values1", Type: "float64", Path: "Values[1]"},
+					{Name: "Tags", Type: "[]string", Path: "Tags"},
+				}},
+			},
+		}},
+		{title: "struct in another package", dir: "testdata/pkg", target: []string{"github.com/ichiban/soa/internal/gen/testdata/models.Order"}, file: File{
+			PackageName: "pkg",
+			exact:       true,
+			Imports: []Import{
+				{Path: `"github.com/ichiban/soa/internal/gen/testdata/models"`},
+				{Path: `"time"`},
+			},
+			Structs: []Struct{
+				{Name: "Order", Package: "models", Fields: []Field{
+					{Name: "ID", Type: "int", Path: "ID"},
+					{Name: "Placed", Type: "time.Time", Path: "Placed"},
+					{Name: "Items", Type: "[]models.Item", Path: "Items"},
+					{Name: "Comment", Type: "string", Path: "Comment"},
+				}},
+			},
+		}},
+		{title: "package of the same name as the template's", dir: "testdata/pkg", target: []string{"github.com/ichiban/soa/internal/gen/testdata/models.Document"}, file: File{
+			PackageName: "pkg",
+			exact:       true,
+			Imports: []Import{
+				{Path: `"github.com/ichiban/soa/internal/gen/testdata/models"`},
+				{Name: "json2", Path: `"github.com/ichiban/soa/internal/gen/testdata/models/json"`},
+			},
+			Structs: []Struct{
+				{Name: "Document", Package: "models", Fields: []Field{
+					{Name: "Title", Type: "string", Path: "Title"},
+					{Name: "Body", Type: "json2.Raw", Path: "Body"},
+				}},
+			},
+		}},
+		{title: "unexported field in another package", dir: "testdata/pkg", target: []string{"github.com/ichiban/soa/internal/gen/testdata/models.Secret"}, err: true},
+		{title: "non struct in another package", dir: "testdata/pkg", target: []string{"github.com/ichiban/soa/internal/gen/testdata/models.Status"}, err: true},
+		{title: "missing type in another package", dir: "testdata/pkg", target: []string{"github.com/ichiban/soa/internal/gen/testdata/models.Missing"}, err: true},
+		{title: "no package", dir: "testdata/nonexistent", err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			f, err := LoadPackage(test.dir, test.config, test.target...)
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+			if !reflect.DeepEqual(test.file, f) {
+				t.Errorf("got %v, want %v", f, test.file)
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package gen
+
+import (
+	"fmt"
+	"go/token"
+	"reflect"
+	"strings"
+)
+
+// Storage strategies of a field.
+const (
+	// StorageColumn stores a field in a column of the field type. i.e. []T
+	StorageColumn = "column"
+	// StorageGroup stores a field in a column of a struct shared with the other fields of the same group.
+	// i.e. `soa:",group=Hot"`
+	StorageGroup = "group"
+	// StorageFlatten stores the fields of a nested struct in columns of their own recursively. i.e. []float64 for each
+	// of Pos.X, Pos.Y, and Pos.Z
+	StorageFlatten = "flatten"
+	// StorageSplit stores the elements of a fixed-size array in columns of their own. i.e. []float32 for each of Pos[0],
+	// Pos[1], and Pos[2]
+	StorageSplit = "split"
+)
+
+// tag is a parsed `soa:"..."` struct tag.
+//
+// The format is `soa:"[name][,storage[=arg]]"`. If the name is "-", the field is skipped. If the name is empty, the column is
+// named after the field.
+type tag struct {
+	Skip    bool
+	Name    string
+	Storage string
+	Group   string
+}
+
+func parseTag(t reflect.StructTag) (tag, error) {
+	v, ok := t.Lookup("soa")
+	if !ok {
+		return tag{}, nil
+	}
+	if v == "-" {
+		return tag{Skip: true}, nil
+	}
+
+	name, opts, _ := strings.Cut(v, ",")
+	tg := tag{Name: name}
+	if name != "" && !token.IsIdentifier(name) {
+		return tag{}, fmt.Errorf("invalid column name %q", name)
+	}
+	if opts == "" {
+		return tg, nil
+	}
+	for _, o := range strings.Split(opts, ",") {
+		k, arg, _ := strings.Cut(o, "=")
+		switch k {
+		case StorageColumn, StorageFlatten, StorageSplit:
+		case StorageGroup:
+			if !token.IsIdentifier(arg) {
+				return tag{}, fmt.Errorf("invalid group name %q", arg)
+			}
+			tg.Group = arg
+		default:
+			return tag{}, fmt.Errorf("unknown option %q", o)
+		}
+		if tg.Storage != "" {
+			return tag{}, fmt.Errorf("multiple storage strategies: %s and %s", tg.Storage, k)
+		}
+		tg.Storage = k
+	}
+	return tg, nil
+}
+
+// groups are the groups of fields specified by tags in order of appearance.
+type groups struct {
+	names  []string
+	fields map[string][]string
+}
+
+func (g *groups) add(t tag, field string) {
+	if t.Skip || t.Group == "" {
+		return
+	}
+	if g.fields == nil {
+		g.fields = map[string][]string{}
+	}
+	if _, ok := g.fields[t.Group]; !ok {
+		g.names = append(g.names, t.Group)
+	}
+	g.fields[t.Group] = append(g.fields[t.Group], field)
+}
+
+func (g *groups) apply(s *Struct) error {
+	for _, n := range g.names {
+		if err := s.Group(n, g.fields[n]...); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+package gen
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseTag(t *testing.T) {
+	tests := []struct {
+		title string
+		tag   reflect.StructTag
+		t     tag
+		err   bool
+	}{
+		{title: "empty"},
+		{title: "other tags", tag: `json:"x"`},
+		{title: "skip", tag: `soa:"-"`, t: tag{Skip: true}},
+		{title: "name", tag: `soa:"Y"`, t: tag{Name: "Y"}},
+		{title: "storage", tag: `soa:",column"`, t: tag{Storage: StorageColumn}},
+		{title: "name and storage", tag: `json:"x" soa:"Y,column"`, t: tag{Name: "Y", Storage: StorageColumn}},
+		{title: "group", tag: `soa:",group=Hot"`, t: tag{Storage: StorageGroup, Group: "Hot"}},
+		{title: "invalid group", tag: `soa:",group="`, err: true},
+		{title: "group and column", tag: `soa:",group=Hot,column"`, err: true},
+		{title: "flatten", tag: `soa:"P,flatten"`, t: tag{Name: "P", Storage: StorageFlatten}},
+		{title: "split", tag: `soa:",split"`, t: tag{Storage: StorageSplit}},
+		{title: "flatten and split", tag: `soa:",flatten,split"`, err: true},
+		{title: "invalid name", tag: `soa:"1st"`, err: true},
+		{title: "unknown option", tag: `soa:",foo"`, err: true},
+		{title: "multiple storages", tag: `soa:",column,column"`, err: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			tg, err := parseTag(test.tag)
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+			if tg != test.t {
+				t.Errorf("got %v, want %v", tg, test.t)
+			}
+		})
+	}
+}
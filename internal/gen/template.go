@@ -0,0 +1,144 @@
+package gen
+
+import (
+	"bytes"
+	_ "embed"
+	"fmt"
+	"go/format"
+	"io"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/tools/imports"
+)
+
+//go:embed soa.go.tmpl
+var soaTemplate string
+
+var defaultTemplate = must(NewTemplate(""))
+
+//go:embed soa_test.go.tmpl
+var testTemplate string
+
+var defaultTestTemplate = must(newTemplate("soa_test.go.tmpl", testTemplate))
+
+// Funcs are the functions available in templates in addition to the methods of File, Struct, and Field.
+var Funcs = template.FuncMap{
+	"join":       strings.Join,
+	"split":      strings.Split,
+	"lower":      strings.ToLower,
+	"upper":      strings.ToUpper,
+	"hasPrefix":  strings.HasPrefix,
+	"hasSuffix":  strings.HasSuffix,
+	"trimPrefix": strings.TrimPrefix,
+	"trimSuffix": strings.TrimSuffix,
+	"quote":      strconv.Quote,
+	// title uppercases the first letter. i.e. deleted -> Deleted
+	"title": func(s string) string {
+		r, n := utf8.DecodeRuneInString(s)
+		return string(unicode.ToUpper(r)) + s[n:]
+	},
+	// untitle lowercases the first letter. i.e. ID -> iD
+	"untitle": func(s string) string {
+		r, n := utf8.DecodeRuneInString(s)
+		return string(unicode.ToLower(r)) + s[n:]
+	},
+}
+
+// Template generates the code of a File with a main template followed by extra templates. All of them share the
+// templates they define so that an extra template can use the ones defined in the main template and vice versa.
+type Template struct {
+	t      *template.Template
+	extras []string
+	// exact is true if the templates import exactly the packages they use, which is the case for the default
+	// template without extra templates.
+	exact bool
+}
+
+// NewTemplate parses the main template named soa.go.tmpl. If text is empty, it's the default template which generates
+// SoA slices.
+func NewTemplate(text string) (*Template, error) {
+	if text == "" {
+		t, err := newTemplate("soa.go.tmpl", soaTemplate)
+		if err != nil {
+			return nil, err
+		}
+		t.exact = true
+		return t, nil
+	}
+	return newTemplate("soa.go.tmpl", text)
+}
+
+func newTemplate(name, text string) (*Template, error) {
+	t, err := template.New(name).Funcs(Funcs).Parse(text)
+	if err != nil {
+		return nil, err
+	}
+	return &Template{t: t}, nil
+}
+
+// AddExtra parses an extra template named name. Its output is appended to the generated code. Neither name nor the
+// templates it defines can replace the ones already defined, i.e. soa.go.tmpl, check, or another extra template.
+func (t *Template) AddExtra(name, text string) error {
+	if t.t.Lookup(name) != nil {
+		return fmt.Errorf("template %s is already defined", name)
+	}
+	// Parse into a clone so that a failed extra template leaves the defined ones intact.
+	c, err := t.t.Clone()
+	if err != nil {
+		return err
+	}
+	if _, err := c.New(name).Parse(text); err != nil {
+		return err
+	}
+	for _, d := range t.t.Templates() {
+		if c.Lookup(d.Name()).Tree != d.Tree {
+			return fmt.Errorf("template %s redefines %s", name, d.Name())
+		}
+	}
+	t.t = c
+	t.extras = append(t.extras, name)
+	t.exact = false
+	return nil
+}
+
+// Execute writes the formatted code generated from f. Imports used by the templates are added automatically unless
+// both the templates and f import exactly the packages the code uses.
+func (t *Template) Execute(w io.Writer, f *File) (int64, error) {
+	var buf bytes.Buffer
+	if err := t.t.Execute(&buf, f); err != nil {
+		return 0, err
+	}
+	for _, name := range t.extras {
+		buf.WriteString("\n")
+		if err := t.t.ExecuteTemplate(&buf, name, f); err != nil {
+			return 0, err
+		}
+	}
+
+	var (
+		b   []byte
+		err error
+	)
+	if t.exact && f.exact {
+		b, err = format.Source(buf.Bytes())
+	} else {
+		b, err = imports.Process("", buf.Bytes(), nil)
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	n, err := w.Write(b)
+	return int64(n), err
+}
+
+func must[T any](v T, err error) T {
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
@@ -0,0 +1,216 @@
+package gen
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTemplate_Execute(t *testing.T) {
+	f := File{
+		PackageName: "test",
+		Structs: []Struct{
+			{Name: "Point", SliceName: "PointSlice", Fields: []Field{
+				{Name: "X", Type: "int", Path: "X"},
+				{Name: "deleted", Type: "bool", Path: "deleted"},
+			}},
+		},
+	}
+
+	tests := []struct {
+		title  string
+		main   string
+		extras [][2]string
+		out    string
+		err    bool
+	}{
+		{
+			title: "main",
+			main: `package {{.PackageName}}
+{{range .Structs}}
+// {{.SliceName}} has {{len .Fields}} columns.
+type {{.SliceName}} struct{}
+{{- end}}
+`,
+			out: `package test
+
+// PointSlice has 2 columns.
+type PointSlice struct{}
+`,
+		},
+		{
+			title: "extras",
+			main: `package {{.PackageName}}
+{{define "columns"}}{{range $i, $f := .Fields}}{{if $i}}, {{end}}{{quote $f.Name}}{{end}}{{end}}`,
+			extras: [][2]string{
+				{"names", `{{range .Structs}}
+func (s {{.SliceType}}) Names() []string {
+	return []string{ {{- template "columns" .}}}
+}
+{{end}}`},
+				{"funcs", `{{range .Structs}}{{range .Fields}}
+// {{.Getter}} {{title .Name}} {{untitle .Name}} {{upper .Name}} {{lower .Name}} {{join (split "a,b" ",") "-"}}
+{{- end}}{{end}}
+func init() {
+	fmt.Println("imported")
+}
+`},
+			},
+			out: `package test
+
+import "fmt"
+
+func (s PointSlice) Names() []string {
+	return []string{"X", "deleted"}
+}
+
+// GetX X x X x a-b
+// getDeleted Deleted deleted DELETED deleted a-b
+func init() {
+	fmt.Println("imported")
+}
+`,
+		},
+		{
+			title: "invalid main",
+			main:  `{{`,
+			err:   true,
+		},
+		{
+			title:  "invalid extra",
+			main:   `package {{.PackageName}}`,
+			extras: [][2]string{{"invalid", `{{end}}`}},
+			err:    true,
+		},
+		{
+			title: "execution error",
+			main:  `package {{.PackageName}}{{.Unknown}}`,
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			var sb strings.Builder
+			err := func() error {
+				tmpl, err := NewTemplate(test.main)
+				if err != nil {
+					return err
+				}
+				for _, e := range test.extras {
+					if err := tmpl.AddExtra(e[0], e[1]); err != nil {
+						return err
+					}
+				}
+				_, err = tmpl.Execute(&sb, &f)
+				return err
+			}()
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+			if got, want := sb.String(), test.out; got != want {
+				t.Errorf("got %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestTemplate_Execute_imports(t *testing.T) {
+	tests := []struct {
+		title  string
+		exact  bool
+		extras [][2]string
+		// resolved is true if the missing import of time is added.
+		resolved bool
+	}{
+		{title: "inexact file", resolved: true},
+		{title: "exact file", exact: true},
+		{title: "exact file with extras", exact: true, extras: [][2]string{{"empty", ""}}, resolved: true},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			// The import of time is missing on purpose to tell if the imports are resolved.
+			f := File{PackageName: "test", exact: test.exact, Structs: []Struct{
+				{Name: "Event", SliceName: "EventSlice", Fields: []Field{{Name: "At", Type: "time.Time", Path: "At"}}},
+			}}
+			tmpl, err := NewTemplate("")
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range test.extras {
+				if err := tmpl.AddExtra(e[0], e[1]); err != nil {
+					t.Fatal(err)
+				}
+			}
+			var sb strings.Builder
+			if _, err := tmpl.Execute(&sb, &f); err != nil {
+				t.Fatal(err)
+			}
+			if got := strings.Contains(sb.String(), `"time"`); got != test.resolved {
+				t.Errorf("got %v, want %v", got, test.resolved)
+			}
+		})
+	}
+}
+
+func TestTemplate_AddExtra(t *testing.T) {
+	tests := []struct {
+		title  string
+		main   string
+		extras [][2]string
+		err    bool
+	}{
+		{
+			title:  "ok",
+			extras: [][2]string{{"a.tmpl", `{{template "check" .}}`}, {"b.tmpl", `{{define "b"}}{{end}}`}},
+		},
+		{
+			title:  "duplicate",
+			extras: [][2]string{{"a.tmpl", ``}, {"a.tmpl", ``}},
+			err:    true,
+		},
+		{
+			title:  "main",
+			extras: [][2]string{{"soa.go.tmpl", ``}},
+			err:    true,
+		},
+		{
+			title:  "defined in main",
+			extras: [][2]string{{"check", ``}},
+			err:    true,
+		},
+		{
+			title:  "defined in custom main",
+			main:   `package {{.PackageName}}{{define "columns"}}{{end}}`,
+			extras: [][2]string{{"columns", ``}},
+			err:    true,
+		},
+		{
+			title:  "redefining main",
+			extras: [][2]string{{"a.tmpl", `{{define "check"}}panic("unreachable"){{end}}`}},
+			err:    true,
+		},
+		{
+			title:  "redefining another extra",
+			extras: [][2]string{{"a.tmpl", `{{define "b"}}b{{end}}`}, {"c.tmpl", `{{define "b"}}c{{end}}`}},
+			err:    true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			tmpl, err := NewTemplate(test.main)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, e := range test.extras {
+				if err = tmpl.AddExtra(e[0], e[1]); err != nil {
+					break
+				}
+			}
+			if test.err != (err != nil) {
+				t.Errorf("error: %v", err)
+			}
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package testdata
+
+import (
+	"time"
+)
+
+type Base struct {
+	ID int
+}
+
+type Meta struct {
+	Tags []string
+}
+
+type Embedded struct {
+	Base
+	*Meta
+	time.Time
+	Name string
+}
+
+type Box[T any] struct {
+	V T
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type EmbeddedGeneric struct {
+	Box[int]
+	*Pair[string, int]
+}
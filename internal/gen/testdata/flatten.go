@@ -0,0 +1,35 @@
+package testdata
+
+import (
+	"time"
+)
+
+type Vec3 struct {
+	X, Y, Z float64
+}
+
+type Box struct {
+	Min, Max Vec3
+}
+
+type Particle struct {
+	Pos    Vec3 `soa:",flatten"`
+	Vel    Vec3 `soa:"V,flatten"`
+	Bounds Box  `soa:",flatten"`
+	Mass   float64
+	Born   time.Time
+	Label  struct {
+		Text  string
+		Color Vec3 `soa:",column"`
+	}
+}
+
+type Body3 struct {
+	Base
+	Vec3 `soa:",flatten"`
+	Mass float64
+}
+
+type NotStruct struct {
+	Mass float64 `soa:",flatten"`
+}
@@ -0,0 +1,14 @@
+package testdata
+
+type Number interface {
+	~int | ~float64
+}
+
+type Pair[K comparable, V any] struct {
+	Key   K
+	Value V
+}
+
+type Vec[T Number] struct {
+	X, Y T
+}
@@ -0,0 +1,15 @@
+package testdata
+
+type Body struct {
+	X, Y     float64 `soa:",group=Hot"`
+	VX, VY   float64 `soa:",group=Hot"`
+	Name     string  `soa:",group=Cold"`
+	Mass     float64
+	Comments []string `soa:"Notes,group=Cold"`
+}
+
+type GroupedPair[K comparable, V any] struct {
+	Key   K `soa:",group=KV"`
+	Value V `soa:",group=KV"`
+	Count int
+}
@@ -0,0 +1,8 @@
+package models
+
+import "github.com/ichiban/soa/internal/gen/testdata/models/json"
+
+type Document struct {
+	Title string
+	Body  json.Raw
+}
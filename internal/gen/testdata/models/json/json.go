@@ -0,0 +1,6 @@
+// Package json has the same name as encoding/json which the generated code may import.
+package json
+
+type Raw struct {
+	Text string
+}
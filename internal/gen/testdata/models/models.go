@@ -0,0 +1,31 @@
+package models
+
+import (
+	"io"
+	"time"
+)
+
+type Order struct {
+	ID      int
+	Placed  time.Time
+	Items   []Item
+	Comment string
+}
+
+type Item struct {
+	SKU      string
+	Quantity int
+}
+
+type Secret struct {
+	Name  string
+	token string
+}
+
+type Status int
+
+type Stream struct {
+	io.Reader
+	Name string
+	_    [8]byte
+}
@@ -0,0 +1,22 @@
+package pkg
+
+import (
+	"net/url"
+	"strings"
+	t "time"
+)
+
+type Point struct {
+	X, Y int
+	Loc  Location
+}
+
+type Event struct {
+	At   t.Time
+	URL  *url.URL
+	Note Note
+}
+
+func trim(s string) string {
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,23 @@
+package pkg
+
+type Location struct {
+	Name string
+}
+
+type Note = string
+
+type Coord Point
+
+type Tagged[T any] struct {
+	Value T
+	Tags  []string
+}
+
+type ID int
+
+const channels = 2
+
+type Sample struct {
+	Values [channels]float64
+	Tags   []string
+}
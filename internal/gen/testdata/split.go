@@ -0,0 +1,26 @@
+package testdata
+
+const size = 2
+
+type RGBA [4]uint8
+
+type Vertex struct {
+	Pos    [3]float32 `soa:",split"`
+	Color  RGBA       `soa:"C,split"`
+	UV     [size]float32
+	Normal [3]float32
+	Bones  [2]Vec3 `soa:",split"`
+}
+
+type NotArray struct {
+	Pos []float32 `soa:",split"`
+}
+
+type Large struct {
+	Small  [16]float32
+	Tagged [17]float32 `soa:",split"`
+}
+
+type TooLarge struct {
+	Matrix [17]float32
+}
@@ -0,0 +1,32 @@
+package testdata
+
+type Blank struct {
+	X int
+	_ int
+}
+
+type Stringer interface {
+	String() string
+}
+
+type EmbeddedInterface struct {
+	Stringer
+	X int
+}
+
+type Node struct {
+	Next  *Node
+	Child Child
+}
+
+type Child struct {
+	Parent Node
+}
+
+type Celsius float64
+
+type Problems struct {
+	_ int
+	X int `soa:",unknown"`
+	Y int `soa:",flatten"`
+}
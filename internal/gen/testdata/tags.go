@@ -0,0 +1,27 @@
+package testdata
+
+type Tagged struct {
+	ID    int            `db:"id,pk"`
+	Name  string         `json:"name" soa:"Names"`
+	Score float64        `soa:",column"`
+	cache map[string]int `soa:"-"`
+	buf   []byte         `soa:"-"`
+}
+
+type UnknownOption struct {
+	X int `soa:",unknown"`
+}
+
+type DuplicateColumn struct {
+	X int
+	Y int `soa:"X"`
+}
+
+type MethodConflict struct {
+	X int
+	Y int `soa:"GetX"`
+}
+
+type MultipleNames struct {
+	X, Y int `soa:"Z"`
+}
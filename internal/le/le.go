@@ -0,0 +1,31 @@
+// Package le provides the memory access to columns of fixed-size values shared by the little-endian binary formats.
+package le
+
+import (
+	"io"
+	"math"
+	"unsafe"
+)
+
+// Native is true if the machine is little-endian so that the memory of a column is already in the format.
+var Native = func() bool {
+	x := uint16(1)
+	return *(*byte)(unsafe.Pointer(&x)) == 1
+}()
+
+// Bytes returns the memory of c.
+func Bytes[T any](c []T) []byte {
+	return unsafe.Slice((*byte)(unsafe.Pointer(unsafe.SliceData(c))), len(c)*int(unsafe.Sizeof(*new(T))))
+}
+
+// Read reads size bytes. The buffer grows as it reads so that a broken size doesn't allocate too much memory at once.
+func Read(r io.Reader, size uint64) ([]byte, error) {
+	b, err := io.ReadAll(io.LimitReader(r, int64(min(size, math.MaxInt64))))
+	if err != nil {
+		return nil, err
+	}
+	if uint64(len(b)) != size {
+		return nil, io.ErrUnexpectedEOF
+	}
+	return b, nil
+}
@@ -28,6 +28,27 @@ type Slicer[S, E any] interface {
 	Grow(n int) S
 }
 
+// Swapper is an optional interface of a Slice which swaps elements column by column. If a Slice implements it, the
+// functions of this package which swap elements use it instead of Get and Set.
+type Swapper interface {
+	// Swap swaps the elements of the indices. i.e. s[i], s[j] = s[j], s[i]
+	Swap(i, j int)
+}
+
+// CopierWithin is an optional interface of a Slice which copies elements within itself column by column. If a Slice
+// implements it, the functions of this package which move elements use it instead of Get and Set.
+type CopierWithin interface {
+	// CopyWithin copies n elements from src to dst even if they overlap. i.e. copy(s[dst:dst+n], s[src:src+n])
+	CopyWithin(dst, src, n int)
+}
+
+// ToSlicer is an optional interface of a Slice which converts itself to a slice of E column by column. If a Slice
+// implements it, ToSlice uses it instead of Get.
+type ToSlicer[E any] interface {
+	// ToSlice returns a new slice containing the elements. i.e. slices.Clone(s)
+	ToSlice() []E
+}
+
 // Make creates a new Slice.
 func Make[S Slice[S, E], E any](len, cap int) S {
 	var s S
@@ -39,7 +60,7 @@ func Make[S Slice[S, E], E any](len, cap int) S {
 func Append[S Slice[S, E], E any](slice S, elems ...E) S {
 	oldLen := slice.Len()
 	newLen := oldLen + len(elems)
-	s := slice.Grow(max(0, newLen-slice.Cap()))
+	s := slice.Grow(len(elems))
 	s = s.Slice(0, newLen, s.Cap())
 	for i, e := range elems {
 		s.Set(oldLen+i, e)
@@ -51,7 +72,7 @@ func Append[S Slice[S, E], E any](slice S, elems ...E) S {
 func Clear[S Slice[S, E], E any](slice S) {
 	var zero E
 	for i := 0; i < slice.Len(); i++ {
-		slice.Set(0, zero)
+		slice.Set(i, zero)
 	}
 }
 
@@ -233,9 +254,10 @@ func Delete[S Slice[S, E], E any](s S, i, j int) S {
 	}
 
 	l := s.Len()
-	s = AppendSeq(s.Slice(0, i, s.Cap()), Values(s.Slice(j, s.Len(), s.Cap())))
-	Clear(s.Slice(s.Len(), l, s.Cap()))
-	return s
+	_ = s.Slice(i, j, l) // bounds check
+	copyWithin(s, i, j, l-j)
+	Clear(s.Slice(l-(j-i), l, s.Cap()))
+	return s.Slice(0, l-(j-i), s.Cap())
 }
 
 // DeleteFunc deletes elements that satisfy the predicate.
@@ -275,6 +297,15 @@ func EqualFunc[S1 Slice[S1, E1], S2 Slice[S2, E2], E1, E2 any](s1 S1, s2 S2, eq
 	return true
 }
 
+// FromSlice returns a new Slice containing the elements of the slice.
+func FromSlice[S Slice[S, E], E any](es []E) S {
+	s := Make[S](len(es), len(es))
+	for i, e := range es {
+		s.Set(i, e)
+	}
+	return s
+}
+
 // Grow grows the capacity of the slice.
 func Grow[S Slice[S, E], E any](s S, n int) S {
 	return s.Grow(n)
@@ -300,14 +331,12 @@ func IndexFunc[S Slice[S, E], E any](s S, f func(E) bool) int {
 // Insert inserts elements to the slice.
 func Insert[S Slice[S, E], E any](s S, i int, v ...E) S {
 	l := s.Len()
+	_ = s.Slice(i, l, l) // bounds check
 	// Make a room for inserted elements at the end.
-	s = s.Grow(l + len(v))
-	c := s.Cap()
-	s = s.Slice(0, l+len(v), c)
+	s = s.Grow(len(v))
+	s = s.Slice(0, l+len(v), s.Cap())
 	// Move the elements following the inserted elements to the end.
-	for j := l - 1; j >= i; j-- {
-		s.Set(j+len(v), s.Get(j))
-	}
+	copyWithin(s, i+len(v), i, l-i)
 	// Insert the elements.
 	for j, v := range v {
 		s.Set(i+j, v)
@@ -380,40 +409,43 @@ func Repeat[S Slice[S, E], E any](x S, count int) S {
 // Replace replaces values.
 func Replace[S Slice[S, E], E any](s S, i, j int, v ...E) S {
 	ol, d := s.Len(), len(v)-(j-i)
+	_ = s.Slice(i, j, ol) // bounds check
 	nl := ol + d
 	if nl > ol {
 		// Make a room for inserted elements at the end.
-		s = s.Grow(nl)
+		s = s.Grow(d)
 		s = s.Slice(0, nl, s.Cap())
 	}
-	// Move the elements following the replaced elements to the end.
-	for k := ol - 1; k >= j; k-- {
-		s.Set(k+d, s.Get(k))
-	}
+	// Move the elements following the replaced elements.
+	copyWithin(s, j+d, j, ol-j)
 	// Replace the elements.
 	for k, v := range v {
 		s.Set(i+k, v)
 	}
+	if nl < ol {
+		// Clear the obsolete elements at the end.
+		Clear(s.Slice(nl, ol, s.Cap()))
+		s = s.Slice(0, nl, s.Cap())
+	}
 	return s
 }
 
 // Reverse reverses the slice.
 func Reverse[S Slice[S, E], E any](s S) {
+	swap := swapper[S, E](s)
 	for i, j := 0, s.Len()-1; i < j; i, j = i+1, j-1 {
-		x, y := s.Get(i), s.Get(j)
-		s.Set(i, y)
-		s.Set(j, x)
+		swap(i, j)
 	}
 }
 
 // SortFunc sorts the slice.
 func SortFunc[S Slice[S, E], E any](x S, cmp func(a, b E) int) {
-	sort.Sort(sortable[S, E]{slice: x, cmp: cmp})
+	sort.Sort(sortable[S, E]{slice: x, cmp: cmp, swap: swapper[S, E](x)})
 }
 
 // SortStableFunc stable sorts the slice.
 func SortStableFunc[S Slice[S, E], E any](x S, cmp func(a, b E) int) {
-	sort.Stable(sortable[S, E]{slice: x, cmp: cmp})
+	sort.Stable(sortable[S, E]{slice: x, cmp: cmp, swap: swapper[S, E](x)})
 }
 
 // SortedFunc collects values from seq into a new sorted slice.
@@ -430,6 +462,19 @@ func SortedStableFunc[S Slice[S, E], E any](seq iter.Seq[E], cmp func(E, E) int)
 	return s
 }
 
+// ToSlice returns a new slice containing the elements of the Slice. If the Slice implements ToSlicer, it's used
+// instead of Get.
+func ToSlice[S Slice[S, E], E any](s S) []E {
+	if s, ok := any(s).(ToSlicer[E]); ok {
+		return s.ToSlice()
+	}
+	es := make([]E, s.Len())
+	for i := range es {
+		es[i] = s.Get(i)
+	}
+	return es
+}
+
 // Values returns an iterator over the Slice.
 func Values[S Slice[S, E], E any](s S) iter.Seq[E] {
 	return func(yield func(E) bool) {
@@ -441,9 +486,42 @@ func Values[S Slice[S, E], E any](s S) iter.Seq[E] {
 	}
 }
 
+// swapper returns a function which swaps elements of the slice.
+func swapper[S Slice[S, E], E any](s S) func(i, j int) {
+	if s, ok := any(s).(Swapper); ok {
+		return s.Swap
+	}
+	return func(i, j int) {
+		x, y := s.Get(i), s.Get(j)
+		s.Set(i, y)
+		s.Set(j, x)
+	}
+}
+
+// copyWithin copies n elements of the slice from src to dst even if they overlap.
+func copyWithin[S Slice[S, E], E any](s S, dst, src, n int) {
+	if n <= 0 || dst == src {
+		return
+	}
+	if s, ok := any(s).(CopierWithin); ok {
+		s.CopyWithin(dst, src, n)
+		return
+	}
+	if dst < src {
+		for k := 0; k < n; k++ {
+			s.Set(dst+k, s.Get(src+k))
+		}
+		return
+	}
+	for k := n - 1; k >= 0; k-- {
+		s.Set(dst+k, s.Get(src+k))
+	}
+}
+
 type sortable[S Slice[S, E], E any] struct {
 	slice S
 	cmp   func(a, b E) int
+	swap  func(i, j int)
 }
 
 func (s sortable[S, E]) Len() int {
@@ -456,7 +534,5 @@ func (s sortable[S, E]) Less(i, j int) bool {
 }
 
 func (s sortable[S, E]) Swap(i, j int) {
-	x, y := s.slice.Get(i), s.slice.Get(j)
-	s.slice.Set(i, y)
-	s.slice.Set(j, x)
+	s.swap(i, j)
 }
@@ -102,6 +102,12 @@ func TestAppend(t *testing.T) {
 	if s.ID[2] != 3 || s.Name[2] != "Bob" {
 		t.Error("User is not appended to slice")
 	}
+
+	// Appending more elements than the remaining capacity.
+	s = Append(s.Slice(0, 2, 3), User{ID: 4, Name: "Dan"}, User{ID: 5, Name: "Eve"})
+	if s.Len() != 4 || s.ID[3] != 5 || s.Name[3] != "Eve" {
+		t.Error("Users are not appended to slice")
+	}
 }
 
 func TestAll(t *testing.T) {
@@ -248,6 +254,32 @@ func TestChunk(t *testing.T) {
 	}
 }
 
+func TestClear(t *testing.T) {
+	tests := []struct {
+		title  string
+		s      UserSlice
+		result UserSlice
+	}{
+		{
+			title: "empty",
+		},
+		{
+			title:  "ok",
+			s:      UserSlice{ID: []int{1, 2, 3}, Name: []string{"Alice", "Bob", "Charlie"}},
+			result: UserSlice{ID: []int{0, 0, 0}, Name: []string{"", "", ""}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			Clear(test.s)
+			if !reflect.DeepEqual(test.s, test.result) {
+				t.Errorf("Clear didn't match: %v != %v", test.s, test.result)
+			}
+		})
+	}
+}
+
 func TestClip(t *testing.T) {
 	users := []User{
 		{ID: 1, Name: "Alice"},
@@ -561,17 +593,28 @@ func TestDelete(t *testing.T) {
 		i      int
 		j      int
 		result UserSlice
-		panics bool
+		// backing is the original slice after the deletion.
+		backing UserSlice
+		panics  bool
 	}{
 		{
 			title: "empty",
 		},
 		{
-			title:  "ok",
-			s:      UserSlice{ID: []int{1, 2, 3, 4, 5}, Name: []string{"Alice", "Bob", "Charlie", "Dan", "Eve"}},
-			i:      2,
-			j:      4,
-			result: UserSlice{ID: []int{1, 2, 5}, Name: []string{"Alice", "Bob", "Eve"}},
+			title:   "ok",
+			s:       UserSlice{ID: []int{1, 2, 3, 4, 5}, Name: []string{"Alice", "Bob", "Charlie", "Dan", "Eve"}},
+			i:       2,
+			j:       4,
+			result:  UserSlice{ID: []int{1, 2, 5}, Name: []string{"Alice", "Bob", "Eve"}},
+			backing: UserSlice{ID: []int{1, 2, 5, 0, 0}, Name: []string{"Alice", "Bob", "Eve", "", ""}},
+		},
+		{
+			title:   "tail",
+			s:       UserSlice{ID: []int{1, 2, 3}, Name: []string{"Alice", "Bob", "Charlie"}},
+			i:       1,
+			j:       3,
+			result:  UserSlice{ID: []int{1}, Name: []string{"Alice"}},
+			backing: UserSlice{ID: []int{1, 0, 0}, Name: []string{"Alice", "", ""}},
 		},
 		{
 			title:  "out of range i",
@@ -601,6 +644,9 @@ func TestDelete(t *testing.T) {
 			if !reflect.DeepEqual(s, test.result) {
 				t.Errorf("Delete didn't match: %v != %v", test.result, s)
 			}
+			if !reflect.DeepEqual(test.s, test.backing) {
+				t.Errorf("Delete didn't clear the obsolete elements: %v != %v", test.s, test.backing)
+			}
 		})
 	}
 }
@@ -727,6 +773,38 @@ func TestEqualFunc(t *testing.T) {
 	}
 }
 
+func TestFromSlice(t *testing.T) {
+	tests := []struct {
+		title  string
+		es     []User
+		result UserSlice
+	}{
+		{
+			title: "empty",
+		},
+		{
+			title: "ok",
+			es: []User{
+				{ID: 1, Name: "Alice"},
+				{ID: 2, Name: "Bob"},
+			},
+			result: UserSlice{ID: []int{1, 2}, Name: []string{"Alice", "Bob"}},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			s := FromSlice[UserSlice](test.es)
+			if !reflect.DeepEqual(s, test.result) {
+				t.Errorf("FromSlice didn't match: %v != %v", s, test.result)
+			}
+			if s.Cap() != len(test.es) {
+				t.Errorf("FromSlice didn't pre-size: %d != %d", s.Cap(), len(test.es))
+			}
+		})
+	}
+}
+
 func TestGrow(t *testing.T) {
 	tests := []struct {
 		title  string
@@ -855,10 +933,28 @@ func TestInsert(t *testing.T) {
 		i      int
 		es     []User
 		result UserSlice
+		cap    int
 	}{
 		{
 			title: "empty",
 		},
+		{
+			title: "within capacity",
+			s: UserSlice{
+				ID:   append(make([]int, 0, 5), 1, 2, 3),
+				Name: append(make([]string, 0, 5), "Alice", "Bob", "Charlie"),
+			},
+			i: 1,
+			es: []User{
+				{ID: 4, Name: "Dan"},
+				{ID: 5, Name: "Eve"},
+			},
+			result: UserSlice{
+				ID:   []int{1, 4, 5, 2, 3},
+				Name: []string{"Alice", "Dan", "Eve", "Bob", "Charlie"},
+			},
+			cap: 5,
+		},
 		{
 			title: "front",
 			s: UserSlice{
@@ -915,6 +1011,9 @@ func TestInsert(t *testing.T) {
 			if !reflect.DeepEqual(result, test.result) {
 				t.Errorf("Insertion didn't match: %v != %v", result, test.result)
 			}
+			if test.cap > 0 && result.Cap() != test.cap {
+				t.Errorf("Insertion reallocated: %d != %d", result.Cap(), test.cap)
+			}
 		})
 	}
 }
@@ -1114,6 +1213,9 @@ func TestReplace(t *testing.T) {
 		i, j   int
 		vs     []User
 		result UserSlice
+		// backing is the original slice after the replacement.
+		backing UserSlice
+		cap     int
 	}{
 		{
 			title: "empty",
@@ -1127,7 +1229,35 @@ func TestReplace(t *testing.T) {
 				{ID: 4, Name: "Dan"},
 				{ID: 5, Name: "Eve"},
 			},
-			result: UserSlice{ID: []int{1, 4, 5, 3}, Name: []string{"Alice", "Dan", "Eve", "Charlie"}},
+			result:  UserSlice{ID: []int{1, 4, 5, 3}, Name: []string{"Alice", "Dan", "Eve", "Charlie"}},
+			backing: UserSlice{ID: []int{1, 2, 3}, Name: []string{"Alice", "Bob", "Charlie"}},
+		},
+		{
+			title: "expand within capacity",
+			s: UserSlice{
+				ID:   append(make([]int, 0, 4), 1, 2, 3),
+				Name: append(make([]string, 0, 4), "Alice", "Bob", "Charlie"),
+			},
+			i: 1,
+			j: 2,
+			vs: []User{
+				{ID: 4, Name: "Dan"},
+				{ID: 5, Name: "Eve"},
+			},
+			result:  UserSlice{ID: []int{1, 4, 5, 3}, Name: []string{"Alice", "Dan", "Eve", "Charlie"}},
+			backing: UserSlice{ID: []int{1, 4, 5}, Name: []string{"Alice", "Dan", "Eve"}},
+			cap:     4,
+		},
+		{
+			title: "shrink",
+			s:     UserSlice{ID: []int{1, 2, 3, 4}, Name: []string{"Alice", "Bob", "Charlie", "Dan"}},
+			i:     0,
+			j:     3,
+			vs: []User{
+				{ID: 5, Name: "Eve"},
+			},
+			result:  UserSlice{ID: []int{5, 4}, Name: []string{"Eve", "Dan"}},
+			backing: UserSlice{ID: []int{5, 4, 0, 0}, Name: []string{"Eve", "Dan", "", ""}},
 		},
 	}
 
@@ -1137,6 +1267,12 @@ func TestReplace(t *testing.T) {
 			if !reflect.DeepEqual(result, test.result) {
 				t.Errorf("Replaced didn't match: %v != %v", result, test.result)
 			}
+			if !reflect.DeepEqual(test.s, test.backing) {
+				t.Errorf("Replace didn't clear the obsolete elements: %v != %v", test.s, test.backing)
+			}
+			if test.cap > 0 && result.Cap() != test.cap {
+				t.Errorf("Replace reallocated: %d != %d", result.Cap(), test.cap)
+			}
 		})
 	}
 }
@@ -1297,6 +1433,24 @@ func TestSortedStableFunc(t *testing.T) {
 	}
 }
 
+func TestToSlice(t *testing.T) {
+	users := []User{
+		{ID: 1, Name: "Alice"},
+		{ID: 2, Name: "Bob"},
+	}
+	particles := []Particle{
+		{X: 1, Y: 2, Z: 3},
+		{VX: 4, VY: 5, VZ: 6, Mass: 7},
+	}
+
+	if result := ToSlice(FromSlice[UserSlice](users)); !reflect.DeepEqual(result, users) {
+		t.Errorf("ToSlice didn't match: %v != %v", result, users)
+	}
+	if result := ToSlice(FromSlice[ParticleSlice](particles)); !reflect.DeepEqual(result, particles) {
+		t.Errorf("ToSlice didn't match: %v != %v", result, particles)
+	}
+}
+
 func TestValues(t *testing.T) {
 	tests := []struct {
 		title  string
@@ -1330,6 +1484,97 @@ func TestValues(t *testing.T) {
 	}
 }
 
+func TestSwapperCopierWithin(t *testing.T) {
+	ps := make([]Particle, 10)
+	for i := range ps {
+		f := float32(i)
+		ps[i] = Particle{X: f, Y: f, Z: f, VX: f, VY: f, VZ: f, Mass: f}
+	}
+
+	tests := []struct {
+		title string
+		soa   func(ParticleSlice) ParticleSlice
+		aos   func([]Particle) []Particle
+	}{
+		{
+			title: "insert",
+			soa: func(s ParticleSlice) ParticleSlice {
+				return Insert(s, 3, ps[7], ps[8])
+			},
+			aos: func(s []Particle) []Particle {
+				return slices.Insert(s, 3, ps[7], ps[8])
+			},
+		},
+		{
+			title: "delete",
+			soa: func(s ParticleSlice) ParticleSlice {
+				return Delete(s, 2, 5)
+			},
+			aos: func(s []Particle) []Particle {
+				return slices.Delete(s, 2, 5)
+			},
+		},
+		{
+			title: "replace expand",
+			soa: func(s ParticleSlice) ParticleSlice {
+				return Replace(s, 2, 3, ps[0], ps[1], ps[2])
+			},
+			aos: func(s []Particle) []Particle {
+				return slices.Replace(s, 2, 3, ps[0], ps[1], ps[2])
+			},
+		},
+		{
+			title: "replace shrink",
+			soa: func(s ParticleSlice) ParticleSlice {
+				return Replace(s, 2, 6, ps[0])
+			},
+			aos: func(s []Particle) []Particle {
+				return slices.Replace(s, 2, 6, ps[0])
+			},
+		},
+		{
+			title: "reverse",
+			soa: func(s ParticleSlice) ParticleSlice {
+				Reverse(s)
+				return s
+			},
+			aos: func(s []Particle) []Particle {
+				slices.Reverse(s)
+				return s
+			},
+		},
+		{
+			title: "sort",
+			soa: func(s ParticleSlice) ParticleSlice {
+				SortStableFunc(s, func(a, b Particle) int {
+					return int(a.X)%3 - int(b.X)%3
+				})
+				return s
+			},
+			aos: func(s []Particle) []Particle {
+				slices.SortStableFunc(s, func(a, b Particle) int {
+					return int(a.X)%3 - int(b.X)%3
+				})
+				return s
+			},
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			s := Make[ParticleSlice](len(ps), len(ps))
+			for i, p := range ps {
+				s.Set(i, p)
+			}
+			s = test.soa(s)
+			result := test.aos(slices.Clone(ps))
+			if !slices.Equal(slices.Collect(Values(s)), result) {
+				t.Errorf("didn't match: %v != %v", slices.Collect(Values(s)), result)
+			}
+		})
+	}
+}
+
 func BenchmarkGravity(b *testing.B) {
 	const dt = float32(0.01)
 	const gravity = float32(-9.8)
@@ -1382,6 +1627,51 @@ func BenchmarkGravity(b *testing.B) {
 	})
 }
 
+func BenchmarkInsert(b *testing.B) {
+	b.Run("with fast paths", benchmarkInsert[ParticleSlice])
+	b.Run("without fast paths", benchmarkInsert[plainParticleSlice])
+}
+
+func benchmarkInsert[S Slice[S, Particle]](b *testing.B) {
+	const numParticles = 10_000
+
+	s := Make[S](numParticles, numParticles+1)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = Insert(s, 0, Particle{})
+	}
+}
+
+func BenchmarkDelete(b *testing.B) {
+	b.Run("with fast paths", benchmarkDelete[ParticleSlice])
+	b.Run("without fast paths", benchmarkDelete[plainParticleSlice])
+}
+
+func benchmarkDelete[S Slice[S, Particle]](b *testing.B) {
+	const numParticles = 10_000
+
+	s := Make[S](numParticles, numParticles)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		_ = Delete(s, 0, 1)
+	}
+}
+
+func BenchmarkReverse(b *testing.B) {
+	b.Run("with fast paths", benchmarkReverse[ParticleSlice])
+	b.Run("without fast paths", benchmarkReverse[plainParticleSlice])
+}
+
+func benchmarkReverse[S Slice[S, Particle]](b *testing.B) {
+	const numParticles = 10_000
+
+	s := Make[S](numParticles, numParticles)
+	b.ResetTimer()
+	for n := 0; n < b.N; n++ {
+		Reverse(s)
+	}
+}
+
 type Particle struct {
 	X, Y, Z    float32
 	VX, VY, VZ float32
@@ -1394,7 +1684,12 @@ type ParticleSlice struct {
 	Mass       []float32
 }
 
-var _ Slice[ParticleSlice, Particle] = ParticleSlice{}
+var (
+	_ Slice[ParticleSlice, Particle] = ParticleSlice{}
+	_ Swapper                        = ParticleSlice{}
+	_ CopierWithin                   = ParticleSlice{}
+	_ ToSlicer[Particle]             = ParticleSlice{}
+)
 
 func (p ParticleSlice) Slice(low, high, max int) ParticleSlice {
 	return ParticleSlice{
@@ -1449,3 +1744,62 @@ func (p ParticleSlice) Len() int {
 func (p ParticleSlice) Cap() int {
 	return min(cap(p.X), cap(p.Y), cap(p.Z), cap(p.VX), cap(p.VY), cap(p.VZ), cap(p.Mass))
 }
+
+func (p ParticleSlice) Swap(i, j int) {
+	p.X[i], p.X[j] = p.X[j], p.X[i]
+	p.Y[i], p.Y[j] = p.Y[j], p.Y[i]
+	p.Z[i], p.Z[j] = p.Z[j], p.Z[i]
+	p.VX[i], p.VX[j] = p.VX[j], p.VX[i]
+	p.VY[i], p.VY[j] = p.VY[j], p.VY[i]
+	p.VZ[i], p.VZ[j] = p.VZ[j], p.VZ[i]
+	p.Mass[i], p.Mass[j] = p.Mass[j], p.Mass[i]
+}
+
+func (p ParticleSlice) CopyWithin(dst, src, n int) {
+	copy(p.X[dst:dst+n], p.X[src:src+n])
+	copy(p.Y[dst:dst+n], p.Y[src:src+n])
+	copy(p.Z[dst:dst+n], p.Z[src:src+n])
+	copy(p.VX[dst:dst+n], p.VX[src:src+n])
+	copy(p.VY[dst:dst+n], p.VY[src:src+n])
+	copy(p.VZ[dst:dst+n], p.VZ[src:src+n])
+	copy(p.Mass[dst:dst+n], p.Mass[src:src+n])
+}
+
+func (p ParticleSlice) ToSlice() []Particle {
+	es := make([]Particle, p.Len())
+	for i := range es {
+		es[i] = p.Get(i)
+	}
+	return es
+}
+
+// plainParticleSlice is a ParticleSlice without Swap and CopyWithin.
+type plainParticleSlice struct {
+	s ParticleSlice
+}
+
+var _ Slice[plainParticleSlice, Particle] = plainParticleSlice{}
+
+func (p plainParticleSlice) Slice(low, high, max int) plainParticleSlice {
+	return plainParticleSlice{s: p.s.Slice(low, high, max)}
+}
+
+func (p plainParticleSlice) Grow(n int) plainParticleSlice {
+	return plainParticleSlice{s: p.s.Grow(n)}
+}
+
+func (p plainParticleSlice) Get(i int) Particle {
+	return p.s.Get(i)
+}
+
+func (p plainParticleSlice) Set(i int, e Particle) {
+	p.s.Set(i, e)
+}
+
+func (p plainParticleSlice) Len() int {
+	return p.s.Len()
+}
+
+func (p plainParticleSlice) Cap() int {
+	return p.s.Cap()
+}
@@ -0,0 +1,284 @@
+package soaarrow
+
+import (
+	"encoding/binary"
+	"slices"
+)
+
+// builder builds a flatbuffer front to back. Unlike the official builders which build back to front, an object is
+// written before the objects it refers to so that all the references point forward.
+type builder struct {
+	b []byte
+}
+
+// field is a field of a table. It's either a scalar or a reference to another object.
+type field struct {
+	size int
+	v    uint64
+	// ref writes the referred object and returns its position.
+	ref func(b *builder) int
+}
+
+func scalar(size int, v uint64) *field {
+	return &field{size: size, v: v}
+}
+
+func ref(f func(b *builder) int) *field {
+	return &field{size: 4, ref: f}
+}
+
+// finish returns the flatbuffer whose root table is written by root.
+func (b *builder) finish(root func(b *builder) int) []byte {
+	b.b = append(b.b[:0], 0, 0, 0, 0)
+	p := root(b)
+	binary.LittleEndian.PutUint32(b.b, uint32(p))
+	return b.b
+}
+
+func (b *builder) align(n int) {
+	for len(b.b)%n != 0 {
+		b.b = append(b.b, 0)
+	}
+}
+
+// table writes a table of the fields in the order of their IDs. A nil field is absent.
+func (b *builder) table(fs ...*field) int {
+	// Larger fields come first so that every field is aligned to its size without much padding.
+	order := make([]int, 0, len(fs))
+	for i, f := range fs {
+		if f != nil {
+			order = append(order, i)
+		}
+	}
+	slices.SortStableFunc(order, func(i, j int) int {
+		return fs[j].size - fs[i].size
+	})
+	offs := make([]int, len(fs))
+	size := 4 // soffset to the vtable
+	for _, i := range order {
+		for size%fs[i].size != 0 {
+			size++
+		}
+		offs[i] = size
+		size += fs[i].size
+	}
+
+	b.align(2)
+	vt := len(b.b)
+	b.b = binary.LittleEndian.AppendUint16(b.b, uint16(4+2*len(fs)))
+	b.b = binary.LittleEndian.AppendUint16(b.b, uint16(size))
+	for _, o := range offs {
+		b.b = binary.LittleEndian.AppendUint16(b.b, uint16(o))
+	}
+
+	b.align(8)
+	t := len(b.b)
+	b.b = append(b.b, make([]byte, size)...)
+	binary.LittleEndian.PutUint32(b.b[t:], uint32(int32(t-vt)))
+	for i, f := range fs {
+		if f == nil || f.ref != nil {
+			continue
+		}
+		p := b.b[t+offs[i]:]
+		switch f.size {
+		case 1:
+			p[0] = byte(f.v)
+		case 2:
+			binary.LittleEndian.PutUint16(p, uint16(f.v))
+		case 4:
+			binary.LittleEndian.PutUint32(p, uint32(f.v))
+		case 8:
+			binary.LittleEndian.PutUint64(p, f.v)
+		}
+	}
+	for i, f := range fs {
+		if f == nil || f.ref == nil {
+			continue
+		}
+		p := f.ref(b)
+		binary.LittleEndian.PutUint32(b.b[t+offs[i]:], uint32(p-(t+offs[i])))
+	}
+	return t
+}
+
+func (b *builder) string(s string) int {
+	b.align(4)
+	p := len(b.b)
+	b.b = binary.LittleEndian.AppendUint32(b.b, uint32(len(s)))
+	b.b = append(b.b, s...)
+	b.b = append(b.b, 0)
+	return p
+}
+
+// tables writes a vector of tables.
+func (b *builder) tables(ts []func(b *builder) int) int {
+	b.align(4)
+	p := len(b.b)
+	b.b = binary.LittleEndian.AppendUint32(b.b, uint32(len(ts)))
+	b.b = append(b.b, make([]byte, 4*len(ts))...)
+	for i, t := range ts {
+		o := p + 4 + 4*i
+		q := t(b)
+		binary.LittleEndian.PutUint32(b.b[o:], uint32(q-o))
+	}
+	return p
+}
+
+// pairs writes a vector of structs of 2 longs, i.e. FieldNode and Buffer.
+func (b *builder) pairs(ps [][2]int64) int {
+	// The elements are aligned to 8 after the length.
+	for (len(b.b)+4)%8 != 0 {
+		b.b = append(b.b, 0)
+	}
+	p := len(b.b)
+	b.b = binary.LittleEndian.AppendUint32(b.b, uint32(len(ps)))
+	for _, e := range ps {
+		b.b = binary.LittleEndian.AppendUint64(b.b, uint64(e[0]))
+		b.b = binary.LittleEndian.AppendUint64(b.b, uint64(e[1]))
+	}
+	return p
+}
+
+// errMalformed is panicked by table when it reads out of the flatbuffer. Reader recovers it.
+type errMalformed struct{}
+
+// table is a table in a flatbuffer. Its methods panic with errMalformed if the flatbuffer is broken.
+type table struct {
+	b []byte
+	p int
+}
+
+// root returns the root table of the flatbuffer b.
+func root(b []byte) table {
+	t := table{b: b}
+	return table{b: b, p: int(t.u32(0))}
+}
+
+func (t table) check(p, n int) {
+	if p < 0 || n < 0 || p > len(t.b) || n > len(t.b)-p {
+		panic(errMalformed{})
+	}
+}
+
+func (t table) u16(p int) uint16 {
+	t.check(p, 2)
+	return binary.LittleEndian.Uint16(t.b[p:])
+}
+
+func (t table) u32(p int) uint32 {
+	t.check(p, 4)
+	return binary.LittleEndian.Uint32(t.b[p:])
+}
+
+func (t table) u64(p int) uint64 {
+	t.check(p, 8)
+	return binary.LittleEndian.Uint64(t.b[p:])
+}
+
+// field returns the position of the field or 0 if it's absent.
+func (t table) field(id int) int {
+	vt := t.p - int(int32(t.u32(t.p)))
+	if 4+2*id+2 > int(t.u16(vt)) {
+		return 0
+	}
+	o := int(t.u16(vt + 4 + 2*id))
+	if o == 0 {
+		return 0
+	}
+	return t.p + o
+}
+
+func (t table) uint8(id int, def uint8) uint8 {
+	p := t.field(id)
+	if p == 0 {
+		return def
+	}
+	t.check(p, 1)
+	return t.b[p]
+}
+
+func (t table) int16(id int, def int16) int16 {
+	p := t.field(id)
+	if p == 0 {
+		return def
+	}
+	return int16(t.u16(p))
+}
+
+func (t table) int32(id int, def int32) int32 {
+	p := t.field(id)
+	if p == 0 {
+		return def
+	}
+	return int32(t.u32(p))
+}
+
+func (t table) int64(id int, def int64) int64 {
+	p := t.field(id)
+	if p == 0 {
+		return def
+	}
+	return int64(t.u64(p))
+}
+
+// ref returns the position of the object the field refers to.
+func (t table) ref(id int) (int, bool) {
+	p := t.field(id)
+	if p == 0 {
+		return 0, false
+	}
+	return p + int(t.u32(p)), true
+}
+
+func (t table) table(id int) (table, bool) {
+	p, ok := t.ref(id)
+	return table{b: t.b, p: p}, ok
+}
+
+func (t table) string(id int) string {
+	p, ok := t.ref(id)
+	if !ok {
+		return ""
+	}
+	n := int(t.u32(p))
+	t.check(p+4, n)
+	return string(t.b[p+4 : p+4+n])
+}
+
+// vector returns the position of the first element and the number of the elements of the vector.
+func (t table) vector(id int) (int, int, bool) {
+	p, ok := t.ref(id)
+	if !ok {
+		return 0, 0, false
+	}
+	return p + 4, int(t.u32(p)), true
+}
+
+// tables returns the tables of the vector.
+func (t table) tables(id int) ([]table, bool) {
+	p, n, ok := t.vector(id)
+	if !ok {
+		return nil, false
+	}
+	t.check(p, 4*n)
+	ts := make([]table, n)
+	for i := range ts {
+		q := p + 4*i
+		ts[i] = table{b: t.b, p: q + int(t.u32(q))}
+	}
+	return ts, true
+}
+
+// pairs returns the structs of 2 longs of the vector.
+func (t table) pairs(id int) ([][2]int64, bool) {
+	p, n, ok := t.vector(id)
+	if !ok {
+		return nil, false
+	}
+	t.check(p, 16*n)
+	ps := make([][2]int64, n)
+	for i := range ps {
+		ps[i] = [2]int64{int64(t.u64(p + 16*i)), int64(t.u64(p + 16*i + 8))}
+	}
+	return ps, true
+}
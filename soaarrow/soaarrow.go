@@ -0,0 +1,734 @@
+// Package soaarrow writes and reads SoA slices in the Arrow IPC streaming format. It's used by the WriteArrow and
+// ReadArrow methods generated by soagen with -arrow.
+//
+// A stream written by Write consists of a schema message, a record batch message of all the rows, and the
+// end-of-stream marker. Reader reads streams of any number of record batches so that streams written by other Arrow
+// implementations can be read as long as their columns are of the supported types:
+//
+//   - bool is bool.
+//   - int8 to int64 and uint8 to uint64 are the integers of the same widths. int and uint are int64 and uint64.
+//   - float32 and float64 are float and double.
+//   - string is utf8.
+//   - time.Time is timestamp in nanoseconds with the time zone UTC. Reader accepts timestamps in any unit and
+//     returns the times in UTC.
+//
+// Written columns are not nullable. Null values in the streams of the other implementations are read as zero values.
+// Dictionary-encoded columns and compressed record batches are not supported.
+package soaarrow
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"time"
+
+	"github.com/ichiban/soa/internal/le"
+)
+
+// The constants of the Arrow flatbuffers schema, i.e. Schema.fbs and Message.fbs.
+const (
+	metadataV4 = 3
+	metadataV5 = 4
+
+	headerSchema          = 1
+	headerDictionaryBatch = 2
+	headerRecordBatch     = 3
+
+	typeInt           = 2
+	typeFloatingPoint = 3
+	typeUtf8          = 5
+	typeBool          = 6
+	typeTimestamp     = 10
+
+	precisionSingle = 1
+	precisionDouble = 2
+
+	unitSecond      = 0
+	unitMillisecond = 1
+	unitMicrosecond = 2
+	unitNanosecond  = 3
+)
+
+// continuation precedes the length of the metadata of a message.
+const continuation = 0xFFFFFFFF
+
+// maxMetadata is the maximum length of the metadata of a message.
+const maxMetadata = 1 << 24
+
+var (
+	timeType = reflect.TypeFor[time.Time]()
+
+	// minTime and maxTime are the range of timestamps in nanoseconds.
+	minTime = time.Unix(0, math.MinInt64)
+	maxTime = time.Unix(0, math.MaxInt64)
+)
+
+// arrowType is the type of a column in the Arrow schema.
+type arrowType struct {
+	id     uint8
+	bits   int32
+	signed bool
+	prec   int16
+	unit   int16
+	tz     string
+}
+
+func (t arrowType) String() string {
+	switch t.id {
+	case typeInt:
+		if t.signed {
+			return fmt.Sprintf("int%d", t.bits)
+		}
+		return fmt.Sprintf("uint%d", t.bits)
+	case typeFloatingPoint:
+		switch t.prec {
+		case precisionSingle:
+			return "float"
+		case precisionDouble:
+			return "double"
+		default:
+			return "halffloat"
+		}
+	case typeUtf8:
+		return "utf8"
+	case typeBool:
+		return "bool"
+	case typeTimestamp:
+		return "timestamp"
+	default:
+		return fmt.Sprintf("type %d", t.id)
+	}
+}
+
+// is reports whether the column of t can be read as u. Timestamps of any unit and time zone are the same.
+func (t arrowType) is(u arrowType) bool {
+	if t.id == typeTimestamp {
+		return u.id == typeTimestamp
+	}
+	return t == u
+}
+
+// buffers returns the number of buffers of the column including the validity bitmap.
+func (t arrowType) buffers() int {
+	if t.id == typeUtf8 {
+		return 3
+	}
+	return 2
+}
+
+// size returns the minimum length of the values buffer of n rows or false if the type is not supported.
+func (t arrowType) size(n int64) (int64, bool) {
+	switch t.id {
+	case typeBool:
+		return (n + 7) / 8, true
+	case typeInt:
+		switch t.bits {
+		case 8, 16, 32, 64:
+			return n * int64(t.bits) / 8, true
+		}
+	case typeFloatingPoint:
+		switch t.prec {
+		case precisionSingle:
+			return 4 * n, true
+		case precisionDouble:
+			return 8 * n, true
+		}
+	case typeUtf8:
+		if n == 0 {
+			return 0, true
+		}
+		// The offsets.
+		return 4 * (n + 1), true
+	case typeTimestamp:
+		return 8 * n, true
+	}
+	return 0, false
+}
+
+func (t arrowType) table(b *builder) int {
+	switch t.id {
+	case typeInt:
+		var signed uint64
+		if t.signed {
+			signed = 1
+		}
+		return b.table(scalar(4, uint64(t.bits)), scalar(1, signed))
+	case typeFloatingPoint:
+		return b.table(scalar(2, uint64(t.prec)))
+	case typeTimestamp:
+		return b.table(scalar(2, uint64(t.unit)), ref(func(b *builder) int {
+			return b.string(t.tz)
+		}))
+	default:
+		return b.table()
+	}
+}
+
+func parseType(id uint8, t table) arrowType {
+	switch id {
+	case typeInt:
+		return arrowType{id: id, bits: t.int32(0, 0), signed: t.uint8(1, 0) != 0}
+	case typeFloatingPoint:
+		return arrowType{id: id, prec: t.int16(0, 0)}
+	case typeTimestamp:
+		return arrowType{id: id, unit: t.int16(0, 0), tz: t.string(1)}
+	default:
+		return arrowType{id: id}
+	}
+}
+
+// Column is a column of an SoA slice to be written or read.
+type Column interface {
+	name() string
+	// typ returns the Arrow type of the column or an error if it can't be written or read.
+	typ() (arrowType, error)
+	// values returns the buffers of the column except the validity bitmap.
+	values() ([][]byte, error)
+	// decode reads the buffers which Reader.Next has checked to be large enough for the rows.
+	decode(t arrowType, validity []byte, nulls int64, bufs [][]byte) error
+	len() int
+}
+
+// Col returns a column named name whose elements are c. A read column is stored in c, so its length has to be the
+// number of the rows of the record batch.
+func Col[T any](name string, c []T) Column {
+	return &column[T]{n: name, c: c}
+}
+
+type column[T any] struct {
+	n string
+	c []T
+}
+
+func (c *column[T]) name() string {
+	return c.n
+}
+
+func (c *column[T]) len() int {
+	return len(c.c)
+}
+
+func (c *column[T]) typ() (arrowType, error) {
+	t := reflect.TypeFor[T]()
+	if t == timeType {
+		return arrowType{id: typeTimestamp, unit: unitNanosecond, tz: "UTC"}, nil
+	}
+	switch t.Kind() {
+	case reflect.Bool:
+		return arrowType{id: typeBool}, nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return arrowType{id: typeInt, bits: bits(t), signed: true}, nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return arrowType{id: typeInt, bits: bits(t)}, nil
+	case reflect.Float32:
+		return arrowType{id: typeFloatingPoint, prec: precisionSingle}, nil
+	case reflect.Float64:
+		return arrowType{id: typeFloatingPoint, prec: precisionDouble}, nil
+	case reflect.String:
+		return arrowType{id: typeUtf8}, nil
+	default:
+		return arrowType{}, fmt.Errorf("soaarrow: unsupported type %s of column %s", t, c.n)
+	}
+}
+
+// bits returns the bit width of the integer type t. int and uint are always 64 bits wide.
+func bits(t reflect.Type) int32 {
+	if k := t.Kind(); k == reflect.Int || k == reflect.Uint {
+		return 64
+	}
+	return int32(8 * t.Size())
+}
+
+func (c *column[T]) values() ([][]byte, error) {
+	t, err := c.typ()
+	if err != nil {
+		return nil, err
+	}
+	switch t.id {
+	case typeBool:
+		b := make([]byte, (len(c.c)+7)/8)
+		for i, e := range c.c {
+			if reflect.ValueOf(e).Bool() {
+				b[i/8] |= 1 << (i % 8)
+			}
+		}
+		return [][]byte{b}, nil
+	case typeUtf8:
+		offsets := make([]byte, 4*(len(c.c)+1))
+		var data []byte
+		for i, e := range c.c {
+			data = append(data, reflect.ValueOf(e).String()...)
+			if len(data) > math.MaxInt32 {
+				return nil, c.errorf("more than %d bytes of strings", math.MaxInt32)
+			}
+			binary.LittleEndian.PutUint32(offsets[4*(i+1):], uint32(len(data)))
+		}
+		return [][]byte{offsets, data}, nil
+	case typeTimestamp:
+		b := make([]byte, 8*len(c.c))
+		for i, e := range c.c {
+			tm := any(e).(time.Time)
+			if tm.Before(minTime) || tm.After(maxTime) {
+				return nil, c.errorf("row %d: %s is out of the range of timestamps in nanoseconds", i, tm)
+			}
+			binary.LittleEndian.PutUint64(b[8*i:], uint64(tm.UnixNano()))
+		}
+		return [][]byte{b}, nil
+	}
+	// The integers and the floating-point numbers are written as they are in memory unless the sizes or the byte order
+	// differ.
+	size := int(t.bits) / 8
+	if t.id == typeFloatingPoint {
+		size = int(reflect.TypeFor[T]().Size())
+	}
+	if le.Native && size == int(reflect.TypeFor[T]().Size()) {
+		return [][]byte{le.Bytes(c.c)}, nil
+	}
+	b := make([]byte, size*len(c.c))
+	for i, e := range c.c {
+		v := reflect.ValueOf(e)
+		var x uint64
+		switch {
+		case v.CanInt():
+			x = uint64(v.Int())
+		case v.CanUint():
+			x = v.Uint()
+		case size == 4:
+			x = uint64(math.Float32bits(float32(v.Float())))
+		default:
+			x = math.Float64bits(v.Float())
+		}
+		for j := range size {
+			b[size*i+j] = byte(x >> (8 * j))
+		}
+	}
+	return [][]byte{b}, nil
+}
+
+func (c *column[T]) decode(t arrowType, validity []byte, nulls int64, bufs [][]byte) error {
+	switch t.id {
+	case typeBool:
+		for i := range c.c {
+			reflect.ValueOf(&c.c[i]).Elem().SetBool(bufs[0][i/8]&(1<<(i%8)) != 0)
+		}
+	case typeUtf8:
+		offsets, data := bufs[0], bufs[1]
+		for i := range c.c {
+			start, end := binary.LittleEndian.Uint32(offsets[4*i:]), binary.LittleEndian.Uint32(offsets[4*(i+1):])
+			if start > end || uint64(end) > uint64(len(data)) {
+				return c.errorf("row %d of offsets %d to %d exceeds the data of %d bytes", i, start, end, len(data))
+			}
+			reflect.ValueOf(&c.c[i]).Elem().SetString(string(data[start:end]))
+		}
+	case typeTimestamp:
+		for i := range c.c {
+			x := int64(binary.LittleEndian.Uint64(bufs[0][8*i:]))
+			var tm time.Time
+			switch t.unit {
+			case unitSecond:
+				tm = time.Unix(x, 0)
+			case unitMillisecond:
+				tm = time.UnixMilli(x)
+			case unitMicrosecond:
+				tm = time.UnixMicro(x)
+			default:
+				tm = time.Unix(0, x)
+			}
+			c.c[i] = any(tm.UTC()).(T)
+		}
+	default:
+		size := int(t.bits) / 8
+		if t.id == typeFloatingPoint {
+			size = int(reflect.TypeFor[T]().Size())
+		}
+		if le.Native && size == int(reflect.TypeFor[T]().Size()) {
+			copy(le.Bytes(c.c), bufs[0])
+			break
+		}
+		for i := range c.c {
+			var x uint64
+			for j := range size {
+				x |= uint64(bufs[0][size*i+j]) << (8 * j)
+			}
+			v := reflect.ValueOf(&c.c[i]).Elem()
+			switch {
+			case v.CanInt():
+				// Sign-extend the integer narrower than 64 bits.
+				s := 64 - 8*size
+				y := int64(x<<s) >> s
+				if v.OverflowInt(y) {
+					return c.errorf("%d overflows %s", y, v.Type())
+				}
+				v.SetInt(y)
+			case v.CanUint():
+				if v.OverflowUint(x) {
+					return c.errorf("%d overflows %s", x, v.Type())
+				}
+				v.SetUint(x)
+			case size == 4:
+				v.SetFloat(float64(math.Float32frombits(uint32(x))))
+			default:
+				v.SetFloat(math.Float64frombits(x))
+			}
+		}
+	}
+	if nulls > 0 && len(validity) > 0 {
+		var zero T
+		for i := range c.c {
+			if validity[i/8]&(1<<(i%8)) == 0 {
+				c.c[i] = zero
+			}
+		}
+	}
+	return nil
+}
+
+func (c *column[T]) errorf(format string, a ...any) error {
+	return fmt.Errorf("soaarrow: column %s: %w", c.n, fmt.Errorf(format, a...))
+}
+
+// Write writes the SoA slice of n rows consisting of the columns to w as a stream of a single record batch.
+func Write(w io.Writer, n int, cs ...Column) error {
+	fields := make([]func(b *builder) int, len(cs))
+	nodes := make([][2]int64, len(cs))
+	var (
+		buffers [][2]int64
+		body    [][]byte
+		offset  int64
+	)
+	for i, c := range cs {
+		t, err := c.typ()
+		if err != nil {
+			return err
+		}
+		if c.len() != n {
+			return fmt.Errorf("soaarrow: column %s has %d rows, want %d", c.name(), c.len(), n)
+		}
+		fields[i] = func(b *builder) int {
+			return b.table(
+				ref(func(b *builder) int { return b.string(c.name()) }),
+				scalar(1, 0), // not nullable
+				scalar(1, uint64(t.id)),
+				ref(t.table),
+				nil,
+				ref(func(b *builder) int { return b.tables(nil) }),
+			)
+		}
+		nodes[i] = [2]int64{int64(n), 0}
+		vs, err := c.values()
+		if err != nil {
+			return err
+		}
+		// The validity bitmap is omitted since there's no null.
+		buffers = append(buffers, [2]int64{offset, 0})
+		for _, v := range vs {
+			buffers = append(buffers, [2]int64{offset, int64(len(v))})
+			body = append(body, v)
+			offset += int64(pad(len(v)))
+		}
+	}
+
+	var b builder
+	schema := message(&b, headerSchema, func(b *builder) int {
+		return b.table(
+			scalar(2, 0), // little-endian
+			ref(func(b *builder) int { return b.tables(fields) }),
+		)
+	}, 0)
+	if err := writeMessage(w, schema, nil); err != nil {
+		return err
+	}
+	batch := message(&b, headerRecordBatch, func(b *builder) int {
+		return b.table(
+			scalar(8, uint64(n)),
+			ref(func(b *builder) int { return b.pairs(nodes) }),
+			ref(func(b *builder) int { return b.pairs(buffers) }),
+		)
+	}, offset)
+	if err := writeMessage(w, batch, body); err != nil {
+		return err
+	}
+	_, err := w.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0})
+	return err
+}
+
+// pad returns n rounded up to a multiple of 8.
+func pad(n int) int {
+	return (n + 7) &^ 7
+}
+
+// message returns the metadata of a message.
+func message(b *builder, headerType uint8, header func(b *builder) int, bodyLength int64) []byte {
+	return b.finish(func(b *builder) int {
+		return b.table(
+			scalar(2, metadataV5),
+			scalar(1, uint64(headerType)),
+			ref(header),
+			scalar(8, uint64(bodyLength)),
+		)
+	})
+}
+
+// writeMessage writes the metadata padded to 8 bytes prefixed with its length and the body of the buffers each
+// padded to 8 bytes.
+func writeMessage(w io.Writer, meta []byte, body [][]byte) error {
+	h := binary.LittleEndian.AppendUint32(nil, continuation)
+	h = binary.LittleEndian.AppendUint32(h, uint32(pad(len(h)+4+len(meta))-len(h)-4))
+	h = append(h, meta...)
+	h = append(h, make([]byte, pad(len(h))-len(h))...)
+	if _, err := w.Write(h); err != nil {
+		return err
+	}
+	var zeros [8]byte
+	for _, b := range body {
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		if _, err := w.Write(zeros[:pad(len(b))-len(b)]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ErrFormat is returned by Reader if the input is not in the format.
+var ErrFormat = errors.New("soaarrow: invalid format")
+
+// Reader reads record batches from an Arrow IPC stream. Next reads a record batch, then Decode reads its columns.
+type Reader struct {
+	r       io.Reader
+	schema  []schemaField
+	rows    int
+	columns []batchColumn
+}
+
+type schemaField struct {
+	name string
+	typ  arrowType
+}
+
+// batchColumn is a column of a record batch.
+type batchColumn struct {
+	nulls    int64
+	validity []byte
+	bufs     [][]byte
+}
+
+// NewReader returns a Reader which reads from r.
+func NewReader(r io.Reader) *Reader {
+	return &Reader{r: r}
+}
+
+// Next reads the schema if it hasn't yet and the next record batch, and returns the number of its rows. It returns
+// io.EOF at the end of the stream. The record batch is rejected unless the buffers of all its columns can hold the rows,
+// so the rows can be allocated before Decode.
+func (r *Reader) Next() (n int, err error) {
+	defer func() {
+		if p := recover(); p != nil {
+			if _, ok := p.(errMalformed); !ok {
+				panic(p)
+			}
+			n, err = 0, fmt.Errorf("%w: broken metadata", ErrFormat)
+		}
+	}()
+	if r.schema == nil {
+		if err := r.readSchema(); err != nil {
+			return 0, err
+		}
+	}
+	m, body, err := r.message()
+	if err != nil {
+		return 0, err
+	}
+	switch t := m.uint8(1, 0); t {
+	case headerRecordBatch:
+	case headerDictionaryBatch:
+		return 0, errors.New("soaarrow: dictionary-encoded columns are not supported")
+	default:
+		return 0, fmt.Errorf("%w: message of type %d, want a record batch", ErrFormat, t)
+	}
+	h, ok := m.table(2)
+	if !ok {
+		return 0, fmt.Errorf("%w: record batch without header", ErrFormat)
+	}
+	if _, ok := h.table(3); ok {
+		return 0, errors.New("soaarrow: compressed record batches are not supported")
+	}
+	rows := h.int64(0, 0)
+	if rows < 0 || rows > math.MaxInt32 {
+		return 0, fmt.Errorf("%w: %d rows", ErrFormat, rows)
+	}
+	nodes, ok := h.pairs(1)
+	if !ok {
+		return 0, fmt.Errorf("%w: record batch without nodes", ErrFormat)
+	}
+	bufs, ok := h.pairs(2)
+	if !ok {
+		return 0, fmt.Errorf("%w: record batch without buffers", ErrFormat)
+	}
+	columns, err := r.batchColumns(rows, nodes, bufs, body)
+	if err != nil {
+		return 0, err
+	}
+	r.rows, r.columns = int(rows), columns
+	return r.rows, nil
+}
+
+// batchColumns returns the columns of a record batch of the rows. It checks that the buffers of every column are in
+// the body and large enough for the rows so that the rows can be allocated safely.
+func (r *Reader) batchColumns(rows int64, nodes, bufs [][2]int64, body []byte) ([]batchColumn, error) {
+	if len(r.schema) == 0 && rows > 0 {
+		return nil, fmt.Errorf("%w: %d rows without columns", ErrFormat, rows)
+	}
+	columns := make([]batchColumn, len(r.schema))
+	b := 0
+	for i, f := range r.schema {
+		size, ok := f.typ.size(rows)
+		if !ok {
+			return nil, fmt.Errorf("soaarrow: column %s of unsupported type %s", f.name, f.typ)
+		}
+		if i >= len(nodes) || nodes[i][0] != rows {
+			return nil, fmt.Errorf("%w: no node of %d rows for column %s", ErrFormat, rows, f.name)
+		}
+		n := f.typ.buffers()
+		if b+n > len(bufs) {
+			return nil, fmt.Errorf("%w: no buffers for column %s", ErrFormat, f.name)
+		}
+		bs := make([][]byte, n)
+		for j, buf := range bufs[b : b+n] {
+			off, l := buf[0], buf[1]
+			if off < 0 || l < 0 || off > int64(len(body)) || l > int64(len(body))-off {
+				return nil, fmt.Errorf("%w: buffer of column %s exceeds the body", ErrFormat, f.name)
+			}
+			bs[j] = body[off : off+l]
+		}
+		b += n
+		if int64(len(bs[1])) < size {
+			return nil, fmt.Errorf("%w: values of %d bytes for %d rows of column %s", ErrFormat, len(bs[1]), rows, f.name)
+		}
+		nulls := nodes[i][1]
+		if nulls > 0 && len(bs[0]) > 0 && int64(len(bs[0])) < (rows+7)/8 {
+			return nil, fmt.Errorf("%w: validity bitmap of %d bytes for %d rows of column %s", ErrFormat, len(bs[0]), rows, f.name)
+		}
+		columns[i] = batchColumn{nulls: nulls, validity: bs[0], bufs: bs[1:]}
+	}
+	return columns, nil
+}
+
+func (r *Reader) readSchema() error {
+	m, _, err := r.message()
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		return err
+	}
+	if t := m.uint8(1, 0); t != headerSchema {
+		return fmt.Errorf("%w: message of type %d, want a schema", ErrFormat, t)
+	}
+	h, ok := m.table(2)
+	if !ok {
+		return fmt.Errorf("%w: schema without header", ErrFormat)
+	}
+	if h.int16(0, 0) != 0 {
+		return errors.New("soaarrow: big-endian streams are not supported")
+	}
+	fs, ok := h.tables(1)
+	if !ok {
+		return fmt.Errorf("%w: schema without fields", ErrFormat)
+	}
+	schema := make([]schemaField, len(fs))
+	for i, f := range fs {
+		name := f.string(0)
+		if _, ok := f.table(4); ok {
+			return fmt.Errorf("soaarrow: dictionary-encoded column %s is not supported", name)
+		}
+		t, _ := f.table(3)
+		schema[i] = schemaField{name: name, typ: parseType(f.uint8(2, 0), t)}
+	}
+	r.schema = schema
+	return nil
+}
+
+// message reads a message and returns its metadata and body. It returns io.EOF at the end of the stream.
+func (r *Reader) message() (table, []byte, error) {
+	var l [4]byte
+	if _, err := io.ReadFull(r.r, l[:]); err != nil {
+		return table{}, nil, err
+	}
+	n := binary.LittleEndian.Uint32(l[:])
+	// Streams prior to Arrow 0.15 don't have the continuation.
+	if n == continuation {
+		if _, err := io.ReadFull(r.r, l[:]); err != nil {
+			return table{}, nil, noEOF(err)
+		}
+		n = binary.LittleEndian.Uint32(l[:])
+	}
+	if n == 0 {
+		return table{}, nil, io.EOF
+	}
+	if n > maxMetadata {
+		return table{}, nil, fmt.Errorf("%w: metadata of %d bytes", ErrFormat, n)
+	}
+	meta := make([]byte, n)
+	if _, err := io.ReadFull(r.r, meta); err != nil {
+		return table{}, nil, noEOF(err)
+	}
+	m := root(meta)
+	if v := m.int16(0, 0); v < metadataV4 {
+		return table{}, nil, fmt.Errorf("soaarrow: unsupported metadata version %d", v)
+	}
+	size := m.int64(3, 0)
+	if size < 0 {
+		return table{}, nil, fmt.Errorf("%w: body of %d bytes", ErrFormat, size)
+	}
+	body, err := le.Read(r.r, uint64(size))
+	if err != nil {
+		return table{}, nil, err
+	}
+	return m, body, nil
+}
+
+func noEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+// Decode reads the columns of the record batch into cs. The columns in the stream and cs must have the same names
+// and types.
+func (r *Reader) Decode(cs ...Column) error {
+	if len(cs) != len(r.schema) {
+		return fmt.Errorf("soaarrow: %d columns, want %d", len(r.schema), len(cs))
+	}
+	byName := make(map[string]Column, len(cs))
+	for _, c := range cs {
+		byName[c.name()] = c
+	}
+	for i, f := range r.schema {
+		c, ok := byName[f.name]
+		if !ok {
+			return fmt.Errorf("soaarrow: unknown column %s", f.name)
+		}
+		delete(byName, f.name)
+		t, err := c.typ()
+		if err != nil {
+			return err
+		}
+		if !t.is(f.typ) {
+			return fmt.Errorf("soaarrow: column %s of type %s, want %s", f.name, f.typ, t)
+		}
+		if c.len() != r.rows {
+			return fmt.Errorf("soaarrow: column %s has %d rows, want %d", f.name, c.len(), r.rows)
+		}
+		b := r.columns[i]
+		if err := c.decode(f.typ, b.validity, b.nulls, b.bufs); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,439 @@
+package soaarrow
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type celsius float64
+
+func TestWrite(t *testing.T) {
+	var b bytes.Buffer
+	if err := Write(&b, 2, Col("X", []int16{1, -1}), Col("Name", []string{"a", "bc"})); err != nil {
+		t.Fatal(err)
+	}
+	in := b.Bytes()
+
+	// next returns the metadata and the body of the message at the beginning of in and advances in.
+	next := func(t *testing.T) (table, []byte) {
+		t.Helper()
+		if c := binary.LittleEndian.Uint32(in); c != continuation {
+			t.Fatalf("got continuation %x", c)
+		}
+		n := int(binary.LittleEndian.Uint32(in[4:]))
+		if (8+n)%8 != 0 {
+			t.Errorf("metadata of %d bytes is not padded", n)
+		}
+		m := root(in[8 : 8+n])
+		if v := m.int16(0, 0); v != metadataV5 {
+			t.Errorf("got version %d", v)
+		}
+		size := int(m.int64(3, 0))
+		if size%8 != 0 {
+			t.Errorf("body of %d bytes is not padded", size)
+		}
+		body := in[8+n : 8+n+size]
+		in = in[8+n+size:]
+		return m, body
+	}
+
+	m, _ := next(t)
+	if ht := m.uint8(1, 0); ht != headerSchema {
+		t.Fatalf("got header type %d, want schema", ht)
+	}
+	s, _ := m.table(2)
+	fs, _ := s.tables(1)
+	if len(fs) != 2 {
+		t.Fatalf("got %d fields", len(fs))
+	}
+	for i, want := range []struct {
+		name string
+		typ  arrowType
+	}{
+		{name: "X", typ: arrowType{id: typeInt, bits: 16, signed: true}},
+		{name: "Name", typ: arrowType{id: typeUtf8}},
+	} {
+		f := fs[i]
+		ty, _ := f.table(3)
+		if name, typ := f.string(0), parseType(f.uint8(2, 0), ty); name != want.name || typ != want.typ {
+			t.Errorf("got field %s of %v, want %s of %v", name, typ, want.name, want.typ)
+		}
+		if children, ok := f.tables(5); !ok || len(children) != 0 {
+			t.Errorf("got children %v", children)
+		}
+	}
+
+	m, body := next(t)
+	if ht := m.uint8(1, 0); ht != headerRecordBatch {
+		t.Fatalf("got header type %d, want record batch", ht)
+	}
+	rb, _ := m.table(2)
+	if n := rb.int64(0, 0); n != 2 {
+		t.Errorf("got %d rows", n)
+	}
+	if nodes, _ := rb.pairs(1); !reflect.DeepEqual(nodes, [][2]int64{{2, 0}, {2, 0}}) {
+		t.Errorf("got nodes %v", nodes)
+	}
+	bufs, _ := rb.pairs(2)
+	if want := [][2]int64{{0, 0}, {0, 4}, {8, 0}, {8, 12}, {24, 3}}; !reflect.DeepEqual(bufs, want) {
+		t.Errorf("got buffers %v, want %v", bufs, want)
+	}
+	want := []byte("\x01\x00\xff\xff\x00\x00\x00\x00" +
+		"\x00\x00\x00\x00\x01\x00\x00\x00\x03\x00\x00\x00\x00\x00\x00\x00" +
+		"abc\x00\x00\x00\x00\x00")
+	if !bytes.Equal(body, want) {
+		t.Errorf("got body %q, want %q", body, want)
+	}
+
+	if !bytes.Equal(in, []byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0}) {
+		t.Errorf("got end of stream %q", in)
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		title string
+		c     any
+	}{
+		{title: "bool", c: []bool{true, false, true, true, false, false, true, false, true}},
+		{title: "int", c: []int{1, -2, 1 << 40}},
+		{title: "int8", c: []int8{1, -2, 127}},
+		{title: "uint", c: []uint{0, 1, 1 << 40}},
+		{title: "uint64", c: []uint64{0, 1, 1 << 63}},
+		{title: "float32", c: []float32{0.5, -1, 3}},
+		{title: "named float64", c: []celsius{36.5, -273.15, 0}},
+		{title: "string", c: []string{"", "foo", "bar baz"}},
+		{title: "time", c: []time.Time{time.Unix(0, 0).UTC(), time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC)}},
+		{title: "empty", c: []string{}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			c := reflect.ValueOf(test.c)
+			got := reflect.MakeSlice(c.Type(), c.Len(), c.Len())
+
+			var b bytes.Buffer
+			if err := Write(&b, c.Len(), col(c, "C")); err != nil {
+				t.Fatal(err)
+			}
+			r := NewReader(&b)
+			n, err := r.Next()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != c.Len() {
+				t.Fatalf("got %d rows, want %d", n, c.Len())
+			}
+			if err := r.Decode(col(got, "C")); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.Interface(), test.c) {
+				t.Errorf("got %v, want %v", got, test.c)
+			}
+			if _, err := r.Next(); err != io.EOF {
+				t.Errorf("got %v, want EOF", err)
+			}
+		})
+	}
+}
+
+// col returns a Column of the slice c.
+func col(c reflect.Value, name string) Column {
+	switch c := c.Interface().(type) {
+	case []bool:
+		return Col(name, c)
+	case []int:
+		return Col(name, c)
+	case []int8:
+		return Col(name, c)
+	case []uint:
+		return Col(name, c)
+	case []uint64:
+		return Col(name, c)
+	case []float32:
+		return Col(name, c)
+	case []celsius:
+		return Col(name, c)
+	case []string:
+		return Col(name, c)
+	case []time.Time:
+		return Col(name, c)
+	default:
+		panic(c)
+	}
+}
+
+// stream returns an Arrow IPC stream of the schema of a nullable column X of int32 and a nullable column T of t, and
+// the record batches whose buffers are the validity bitmaps and the values of X and T.
+func stream(continuation bool, t arrowType, batches ...[4][]byte) []byte {
+	var (
+		w bytes.Buffer
+		b builder
+	)
+	write := func(meta []byte, body [][]byte) {
+		if continuation {
+			_ = writeMessage(&w, meta, body)
+			return
+		}
+		// A stream prior to Arrow 0.15 is the same except for the continuation.
+		var m bytes.Buffer
+		_ = writeMessage(&m, meta, body)
+		w.Write(m.Bytes()[4:])
+	}
+	field := func(name string, t arrowType) func(b *builder) int {
+		return func(b *builder) int {
+			return b.table(
+				ref(func(b *builder) int { return b.string(name) }),
+				scalar(1, 1),
+				scalar(1, uint64(t.id)),
+				ref(t.table),
+				nil,
+				ref(func(b *builder) int { return b.tables(nil) }),
+			)
+		}
+	}
+	write(message(&b, headerSchema, func(b *builder) int {
+		return b.table(scalar(2, 0), ref(func(b *builder) int {
+			return b.tables([]func(b *builder) int{
+				field("X", arrowType{id: typeInt, bits: 32, signed: true}),
+				field("T", t),
+			})
+		}))
+	}, 0), nil)
+	for _, bs := range batches {
+		rows := int64(len(bs[1]) / 4)
+		var (
+			nodes   [][2]int64
+			buffers [][2]int64
+			offset  int64
+		)
+		for i, buf := range bs {
+			if i%2 == 0 {
+				var nulls int64
+				for j := range rows {
+					if len(buf) > 0 && buf[j/8]&(1<<(j%8)) == 0 {
+						nulls++
+					}
+				}
+				nodes = append(nodes, [2]int64{rows, nulls})
+			}
+			buffers = append(buffers, [2]int64{offset, int64(len(buf))})
+			offset += int64(pad(len(buf)))
+		}
+		write(message(&b, headerRecordBatch, func(b *builder) int {
+			return b.table(
+				scalar(8, uint64(rows)),
+				ref(func(b *builder) int { return b.pairs(nodes) }),
+				ref(func(b *builder) int { return b.pairs(buffers) }),
+			)
+		}, offset), bs[:])
+	}
+	w.Write([]byte{0xFF, 0xFF, 0xFF, 0xFF, 0, 0, 0, 0})
+	return w.Bytes()
+}
+
+func TestReader(t *testing.T) {
+	ms := arrowType{id: typeTimestamp, unit: unitMillisecond, tz: "Asia/Tokyo"}
+	batches := [][4][]byte{
+		{nil, []byte("\x01\x00\x00\x00\x02\x00\x00\x00"), nil, []byte("\xe8\x03\x00\x00\x00\x00\x00\x00\xd0\x07\x00\x00\x00\x00\x00\x00")},
+		{{0b101}, []byte("\x03\x00\x00\x00\x04\x00\x00\x00\x05\x00\x00\x00"), {0b110}, bytes.Repeat([]byte{1, 0, 0, 0, 0, 0, 0, 0}, 3)},
+	}
+
+	tests := []struct {
+		title string
+		in    []byte
+		x     []int32
+		t     []time.Time
+	}{
+		{
+			title: "batches",
+			in:    stream(true, ms, batches...),
+			x:     []int32{1, 2, 3, 0, 5},
+			t: []time.Time{
+				time.Unix(1, 0).UTC(), time.Unix(2, 0).UTC(),
+				{}, time.UnixMilli(1).UTC(), time.UnixMilli(1).UTC(),
+			},
+		},
+		{
+			title: "without continuation",
+			in:    stream(false, arrowType{id: typeTimestamp, unit: unitSecond}, batches[0]),
+			x:     []int32{1, 2},
+			t:     []time.Time{time.Unix(1000, 0).UTC(), time.Unix(2000, 0).UTC()},
+		},
+		{
+			title: "no batches",
+			in:    stream(true, ms),
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			var (
+				x  []int32
+				ts []time.Time
+			)
+			r := NewReader(bytes.NewReader(test.in))
+			for {
+				n, err := r.Next()
+				if err == io.EOF {
+					break
+				}
+				if err != nil {
+					t.Fatal(err)
+				}
+				cx, ct := make([]int32, n), make([]time.Time, n)
+				if err := r.Decode(Col("T", ct), Col("X", cx)); err != nil {
+					t.Fatal(err)
+				}
+				x, ts = append(x, cx...), append(ts, ct...)
+			}
+			if !reflect.DeepEqual(x, test.x) {
+				t.Errorf("got X %v, want %v", x, test.x)
+			}
+			if !reflect.DeepEqual(ts, test.t) {
+				t.Errorf("got T %v, want %v", ts, test.t)
+			}
+		})
+	}
+}
+
+func TestReader_Error(t *testing.T) {
+	var valid bytes.Buffer
+	if err := Write(&valid, 2, Col("X", []int32{1, 2}), Col("OK", []bool{true, false})); err != nil {
+		t.Fatal(err)
+	}
+	// modify returns a copy of valid with the byte at i replaced.
+	modify := func(i int, b byte) []byte {
+		bs := bytes.Clone(valid.Bytes())
+		bs[i] = b
+		return bs
+	}
+	// rows returns a copy of valid whose record batch and nodes claim n rows.
+	rows := func(n uint64) []byte {
+		return bytes.ReplaceAll(valid.Bytes(), binary.LittleEndian.AppendUint64(nil, 2), binary.LittleEndian.AppendUint64(nil, n))
+	}
+
+	tests := []struct {
+		title string
+		in    []byte
+		cols  func(x []int32, ok []bool) []Column
+		err   error
+		msg   string
+	}{
+		{title: "truncated", in: valid.Bytes()[:valid.Len()-20], err: io.ErrUnexpectedEOF},
+		{title: "empty", in: nil, err: io.ErrUnexpectedEOF},
+		{title: "broken metadata", in: modify(8, 0xFF), err: ErrFormat},
+		{title: "huge metadata", in: modify(7, 0x7F), err: ErrFormat},
+		{title: "huge rows", in: rows(math.MaxInt32), msg: "soaarrow: invalid format: values of 8 bytes for 2147483647 rows of column X"},
+		{title: "more rows", in: rows(3), msg: "soaarrow: invalid format: values of 8 bytes for 3 rows of column X"},
+		{title: "too many rows", in: rows(math.MaxInt32 + 1), err: ErrFormat},
+		{
+			title: "unsupported stream type",
+			in:    stream(true, arrowType{id: typeFloatingPoint}, [4][]byte{nil, []byte("\x01\x00\x00\x00"), nil, []byte("\x00\x3c")}),
+			msg:   "soaarrow: column T of unsupported type halffloat",
+		},
+		{
+			title: "type mismatch",
+			in:    valid.Bytes(),
+			cols: func(x []int32, ok []bool) []Column {
+				return []Column{Col("X", make([]int64, 2)), Col("OK", ok)}
+			},
+			msg: "soaarrow: column X of type int32, want int64",
+		},
+		{
+			title: "unknown column",
+			in:    valid.Bytes(),
+			cols: func(x []int32, ok []bool) []Column {
+				return []Column{Col("Y", x), Col("OK", ok)}
+			},
+			msg: "soaarrow: unknown column X",
+		},
+		{
+			title: "missing column",
+			in:    valid.Bytes(),
+			cols: func(x []int32, ok []bool) []Column {
+				return []Column{Col("X", x)}
+			},
+			msg: "soaarrow: 2 columns, want 1",
+		},
+		{
+			title: "rows",
+			in:    valid.Bytes(),
+			cols: func(x []int32, ok []bool) []Column {
+				return []Column{Col("X", x), Col("OK", make([]bool, 3))}
+			},
+			msg: "soaarrow: column OK has 3 rows, want 2",
+		},
+		{
+			title: "unsupported type",
+			in:    valid.Bytes(),
+			cols: func(x []int32, ok []bool) []Column {
+				return []Column{Col("X", make([][]byte, 2)), Col("OK", ok)}
+			},
+			msg: "soaarrow: unsupported type []uint8 of column X",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			x, ok := make([]int32, 2), make([]bool, 2)
+			cols := []Column{Col("X", x), Col("OK", ok)}
+			if test.cols != nil {
+				cols = test.cols(x, ok)
+			}
+
+			r := NewReader(bytes.NewReader(test.in))
+			_, err := r.Next()
+			if err == nil {
+				err = r.Decode(cols...)
+			}
+			switch {
+			case test.err != nil:
+				if !errors.Is(err, test.err) {
+					t.Errorf("got %v, want %v", err, test.err)
+				}
+			case test.msg != "":
+				if err == nil || err.Error() != test.msg {
+					t.Errorf("got %v, want %s", err, test.msg)
+				}
+			case err != nil:
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func TestWrite_Error(t *testing.T) {
+	tests := []struct {
+		title string
+		cols  []Column
+		msg   string
+	}{
+		{title: "unsupported type", cols: []Column{Col("F", []func(){nil})}, msg: "soaarrow: unsupported type func() of column F"},
+		{title: "rows", cols: []Column{Col("X", []int{1, 2})}, msg: "soaarrow: column X has 2 rows, want 1"},
+		{
+			title: "time out of range",
+			cols:  []Column{Col("T", []time.Time{{}})},
+			msg:   "soaarrow: column T: row 0: 0001-01-01 00:00:00 +0000 UTC is out of the range of timestamps in nanoseconds",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			var b bytes.Buffer
+			err := Write(&b, 1, test.cols...)
+			if err == nil || err.Error() != test.msg {
+				t.Errorf("got %v, want %s", err, test.msg)
+			}
+			if b.Len() != 0 {
+				t.Errorf("wrote %d bytes", b.Len())
+			}
+		})
+	}
+}
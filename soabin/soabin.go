@@ -0,0 +1,517 @@
+// Package soabin encodes and decodes SoA slices in a compact binary columnar format. It's used by the MarshalBinary,
+// UnmarshalBinary, WriteTo, and ReadFrom methods generated by soagen with -binary.
+//
+// The format consists of a header, a schema, and columns. All the integers are little-endian.
+//
+//	header:  "SOAB" version:uint32 rows:uint64 columns:uint32
+//	schema:  (name:string type:string)... for each column
+//	columns: (length:uint64 payload:[length]byte)... for each column in the order of the schema
+//
+// where a string in the schema is length:uint32 followed by the bytes. The type of a column is how its payload is
+// encoded:
+//
+//   - bool, int8 to int64, uint8 to uint64, float32, float64, complex64, complex128, and arrays of them are the raw
+//     little-endian values. A column of them is written and read as a block of memory on little-endian machines.
+//   - int, uint, and uintptr are int64 or uint64.
+//   - string and bytes are the lengths of the elements as uint64 followed by the concatenated elements.
+//   - binary is the values encoded by encoding.BinaryMarshaler, each of which is prefixed with its length as uint64.
+//
+// Columns are decoded by name so that the order of the columns doesn't matter as long as their types agree.
+package soabin
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"reflect"
+	"strings"
+
+	"github.com/ichiban/soa/internal/le"
+)
+
+// Version is the version of the format.
+const Version = 1
+
+const magic = "SOAB"
+
+var (
+	binaryMarshaler   = reflect.TypeFor[encoding.BinaryMarshaler]()
+	binaryUnmarshaler = reflect.TypeFor[encoding.BinaryUnmarshaler]()
+)
+
+// Column is a column of an SoA slice to be encoded or decoded.
+type Column interface {
+	name() string
+	// typ returns how the column is encoded or an error if it can't be.
+	typ() (string, error)
+	// size returns the length of the payload.
+	size() (uint64, error)
+	encode(w io.Writer) error
+	// decode reads the payload of size bytes for the number of the rows.
+	decode(r io.Reader, size uint64, rows int) error
+}
+
+// Col returns a column named name whose elements are c. A decoded column is stored in c, so its length has to be the
+// number of the rows.
+func Col[T any](name string, c []T) Column {
+	return &column[T]{n: name, c: c}
+}
+
+// Into returns a column named name which is decoded into a new slice stored in *c. Unlike Col, the slice is allocated
+// after its payload is read so that a broken number of the rows in the header doesn't allocate too much memory at once.
+// The column is encoded from *c at the time of the call.
+func Into[T any](name string, c *[]T) Column {
+	return &column[T]{n: name, c: *c, p: c}
+}
+
+type column[T any] struct {
+	n string
+	c []T
+	// p is where the decoded column is stored if it's returned by Into.
+	p *[]T
+	// b is the payload of a binary column which has to be marshaled to know its size.
+	b []byte
+}
+
+func (c *column[T]) name() string {
+	return c.n
+}
+
+func (c *column[T]) typ() (string, error) {
+	t := reflect.TypeFor[T]()
+	if s, ok := rawType(t); ok {
+		return s, nil
+	}
+	switch {
+	case t.Kind() == reflect.Int:
+		return "int64", nil
+	case t.Kind() == reflect.Uint, t.Kind() == reflect.Uintptr:
+		return "uint64", nil
+	case t.Kind() == reflect.String:
+		return "string", nil
+	case t.Kind() == reflect.Slice && t.Elem().Kind() == reflect.Uint8:
+		return "bytes", nil
+	case t.Implements(binaryMarshaler) && reflect.PointerTo(t).Implements(binaryUnmarshaler):
+		return "binary", nil
+	default:
+		return "", fmt.Errorf("soabin: unsupported type %s of column %s", t, c.n)
+	}
+}
+
+// rawType returns the type of the column if its elements are encoded as they are in memory.
+func rawType(t reflect.Type) (string, bool) {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128:
+		return t.Kind().String(), true
+	case reflect.Array:
+		s, ok := rawType(t.Elem())
+		return fmt.Sprintf("[%d]%s", t.Len(), s), ok
+	default:
+		return "", false
+	}
+}
+
+func (c *column[T]) size() (uint64, error) {
+	t, err := c.typ()
+	if err != nil {
+		return 0, err
+	}
+	switch t {
+	case "string", "bytes":
+		n := 8 * uint64(len(c.c))
+		for _, e := range c.c {
+			n += uint64(reflect.ValueOf(e).Len())
+		}
+		return n, nil
+	case "binary":
+		c.b = c.b[:0]
+		for _, e := range c.c {
+			b, err := any(e).(encoding.BinaryMarshaler).MarshalBinary()
+			if err != nil {
+				return 0, fmt.Errorf("soabin: column %s: %w", c.n, err)
+			}
+			c.b = binary.LittleEndian.AppendUint64(c.b, uint64(len(b)))
+			c.b = append(c.b, b...)
+		}
+		return uint64(len(c.b)), nil
+	default:
+		return uint64(len(c.c)) * uint64(reflect.TypeFor[T]().Size()), nil
+	}
+}
+
+func (c *column[T]) encode(w io.Writer) error {
+	t, _ := c.typ()
+	switch t {
+	case "int64", "uint64":
+		// int, uint, and uintptr are converted unless they're the same as int64 and uint64 in memory.
+		if reflect.TypeFor[T]().Size() == 8 && le.Native {
+			break
+		}
+		b := make([]byte, 8*len(c.c))
+		for i, e := range c.c {
+			v := reflect.ValueOf(e)
+			if v.CanInt() {
+				binary.LittleEndian.PutUint64(b[8*i:], uint64(v.Int()))
+			} else {
+				binary.LittleEndian.PutUint64(b[8*i:], v.Uint())
+			}
+		}
+		_, err := w.Write(b)
+		return err
+	case "string", "bytes":
+		b := make([]byte, 8*len(c.c))
+		for i, e := range c.c {
+			binary.LittleEndian.PutUint64(b[8*i:], uint64(reflect.ValueOf(e).Len()))
+		}
+		if _, err := w.Write(b); err != nil {
+			return err
+		}
+		for _, e := range c.c {
+			v := reflect.ValueOf(e)
+			var err error
+			if t == "string" {
+				_, err = io.WriteString(w, v.String())
+			} else {
+				_, err = w.Write(v.Bytes())
+			}
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	case "binary":
+		_, err := w.Write(c.b)
+		return err
+	}
+	if !le.Native {
+		return binary.Write(w, binary.LittleEndian, c.c)
+	}
+	_, err := w.Write(le.Bytes(c.c))
+	return err
+}
+
+func (c *column[T]) decode(r io.Reader, size uint64, rows int) error {
+	if c.p == nil {
+		return c.decodePayload(r, size)
+	}
+	b, err := le.Read(r, size)
+	if err != nil {
+		return err
+	}
+	if m := c.rowSize(); m > 0 && uint64(rows) > size/m {
+		return c.errorf("payload of %d bytes for %d rows", size, rows)
+	}
+	c.c = make([]T, rows)
+	if err := c.decodePayload(bytes.NewReader(b), size); err != nil {
+		return err
+	}
+	*c.p = c.c
+	return nil
+}
+
+// rowSize returns the minimum length of the payload for a row.
+func (c *column[T]) rowSize() uint64 {
+	t, _ := c.typ()
+	if _, ok := rawType(reflect.TypeFor[T]()); ok {
+		return uint64(reflect.TypeFor[T]().Size())
+	}
+	switch t {
+	case "int64", "uint64", "string", "bytes", "binary":
+		// The values, the lengths of the elements, or the length prefixes.
+		return 8
+	default:
+		return 0
+	}
+}
+
+func (c *column[T]) decodePayload(r io.Reader, size uint64) error {
+	t, _ := c.typ()
+	switch t {
+	case "int64", "uint64":
+		if reflect.TypeFor[T]().Size() == 8 && le.Native {
+			break
+		}
+		if size != 8*uint64(len(c.c)) {
+			return c.errorf("payload of %d bytes for %d rows", size, len(c.c))
+		}
+		b := make([]byte, size)
+		if _, err := io.ReadFull(r, b); err != nil {
+			return err
+		}
+		for i := range c.c {
+			x := binary.LittleEndian.Uint64(b[8*i:])
+			v := reflect.ValueOf(&c.c[i]).Elem()
+			if v.CanInt() {
+				if v.OverflowInt(int64(x)) {
+					return c.errorf("%d overflows %s", int64(x), v.Type())
+				}
+				v.SetInt(int64(x))
+			} else {
+				if v.OverflowUint(x) {
+					return c.errorf("%d overflows %s", x, v.Type())
+				}
+				v.SetUint(x)
+			}
+		}
+		return nil
+	case "string", "bytes":
+		n := 8 * uint64(len(c.c))
+		if size < n {
+			return c.errorf("payload of %d bytes for %d rows", size, len(c.c))
+		}
+		b, err := le.Read(r, size)
+		if err != nil {
+			return err
+		}
+		data := b[n:]
+		for i := range c.c {
+			l := binary.LittleEndian.Uint64(b[8*i:])
+			if l > uint64(len(data)) {
+				return c.errorf("row %d of %d bytes exceeds the payload", i, l)
+			}
+			v := reflect.ValueOf(&c.c[i]).Elem()
+			switch {
+			case t == "string":
+				v.SetString(string(data[:l]))
+			case l > 0:
+				// An empty element is decoded as nil.
+				v.SetBytes(data[:l:l])
+			}
+			data = data[l:]
+		}
+		if len(data) > 0 {
+			return c.errorf("%d extra bytes", len(data))
+		}
+		return nil
+	case "binary":
+		b, err := le.Read(r, size)
+		if err != nil {
+			return err
+		}
+		for i := range c.c {
+			if len(b) < 8 {
+				return c.errorf("row %d is missing", i)
+			}
+			l := binary.LittleEndian.Uint64(b)
+			b = b[8:]
+			if l > uint64(len(b)) {
+				return c.errorf("row %d of %d bytes exceeds the payload", i, l)
+			}
+			if err := any(&c.c[i]).(encoding.BinaryUnmarshaler).UnmarshalBinary(b[:l]); err != nil {
+				return c.errorf("row %d: %w", i, err)
+			}
+			b = b[l:]
+		}
+		if len(b) > 0 {
+			return c.errorf("%d extra bytes", len(b))
+		}
+		return nil
+	}
+	if size != uint64(len(c.c))*uint64(reflect.TypeFor[T]().Size()) {
+		return c.errorf("payload of %d bytes for %d rows", size, len(c.c))
+	}
+	if !le.Native {
+		if err := binary.Read(r, binary.LittleEndian, c.c); err != nil {
+			return err
+		}
+	} else if _, err := io.ReadFull(r, le.Bytes(c.c)); err != nil {
+		return err
+	}
+	// Any byte other than 0 and 1 is not a bool.
+	if strings.Contains(t, "bool") {
+		for i, b := range le.Bytes(c.c) {
+			if b > 1 {
+				return c.errorf("invalid bool %d at byte %d", b, i)
+			}
+		}
+	}
+	return nil
+}
+
+func (c *column[T]) errorf(format string, a ...any) error {
+	return fmt.Errorf("soabin: column %s: %w", c.n, fmt.Errorf(format, a...))
+}
+
+// Encode writes the SoA slice of n rows consisting of the columns to w. It returns the number of bytes written.
+func Encode(w io.Writer, n int, cs ...Column) (int64, error) {
+	cw := countingWriter{w: w}
+	if err := encode(&cw, n, cs); err != nil {
+		return cw.n, err
+	}
+	return cw.n, nil
+}
+
+func encode(w io.Writer, n int, cs []Column) error {
+	sizes := make([]uint64, len(cs))
+	b := append([]byte(magic), 0, 0, 0, 0)
+	binary.LittleEndian.PutUint32(b[len(magic):], Version)
+	b = binary.LittleEndian.AppendUint64(b, uint64(n))
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(cs)))
+	for i, c := range cs {
+		t, err := c.typ()
+		if err != nil {
+			return err
+		}
+		if sizes[i], err = c.size(); err != nil {
+			return err
+		}
+		b = appendString(b, c.name())
+		b = appendString(b, t)
+	}
+	if _, err := w.Write(b); err != nil {
+		return err
+	}
+	for i, c := range cs {
+		if _, err := w.Write(binary.LittleEndian.AppendUint64(nil, sizes[i])); err != nil {
+			return err
+		}
+		if err := c.encode(w); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func appendString(b []byte, s string) []byte {
+	b = binary.LittleEndian.AppendUint32(b, uint32(len(s)))
+	return append(b, s...)
+}
+
+// ErrFormat is returned by Decoder if the input is not in the format.
+var ErrFormat = errors.New("soabin: invalid format")
+
+// Decoder reads an SoA slice. Len reads the header and the schema, then Decode reads the columns.
+type Decoder struct {
+	r      countingReader
+	rows   int
+	schema []field
+}
+
+type field struct {
+	name, typ string
+}
+
+// NewDecoder returns a Decoder which reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{r: countingReader{r: r}}
+}
+
+// Count returns the number of bytes read so far.
+func (d *Decoder) Count() int64 {
+	return d.r.n
+}
+
+// Len reads the header and the schema and returns the number of rows. The number is not checked against the columns
+// until Decode reads them, so decode the columns with Into instead of allocating them by the number if the input is
+// not trusted.
+func (d *Decoder) Len() (int, error) {
+	var h [len(magic) + 4 + 8 + 4]byte
+	if _, err := io.ReadFull(&d.r, h[:]); err != nil {
+		return 0, err
+	}
+	if string(h[:len(magic)]) != magic {
+		return 0, ErrFormat
+	}
+	if v := binary.LittleEndian.Uint32(h[len(magic):]); v != Version {
+		return 0, fmt.Errorf("soabin: unsupported version %d", v)
+	}
+	n := binary.LittleEndian.Uint64(h[len(magic)+4:])
+	if n > uint64(math.MaxInt) {
+		return 0, fmt.Errorf("%w: %d rows", ErrFormat, n)
+	}
+	cols := binary.LittleEndian.Uint32(h[len(magic)+12:])
+	d.schema = d.schema[:0]
+	for range cols {
+		name, err := d.string()
+		if err != nil {
+			return 0, err
+		}
+		typ, err := d.string()
+		if err != nil {
+			return 0, err
+		}
+		d.schema = append(d.schema, field{name: name, typ: typ})
+	}
+	d.rows = int(n)
+	return d.rows, nil
+}
+
+// maxString is the maximum length of a name or a type in the schema.
+const maxString = 1 << 16
+
+func (d *Decoder) string() (string, error) {
+	var l [4]byte
+	if _, err := io.ReadFull(&d.r, l[:]); err != nil {
+		return "", err
+	}
+	n := binary.LittleEndian.Uint32(l[:])
+	if n > maxString {
+		return "", fmt.Errorf("%w: string of %d bytes in the schema", ErrFormat, n)
+	}
+	b := make([]byte, n)
+	if _, err := io.ReadFull(&d.r, b); err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+// Decode reads the columns into cs. The columns in the input and cs must have the same names and types.
+func (d *Decoder) Decode(cs ...Column) error {
+	if len(cs) != len(d.schema) {
+		return fmt.Errorf("soabin: %d columns, want %d", len(d.schema), len(cs))
+	}
+	byName := make(map[string]Column, len(cs))
+	for _, c := range cs {
+		byName[c.name()] = c
+	}
+	for _, f := range d.schema {
+		c, ok := byName[f.name]
+		if !ok {
+			return fmt.Errorf("soabin: unknown column %s", f.name)
+		}
+		delete(byName, f.name)
+		t, err := c.typ()
+		if err != nil {
+			return err
+		}
+		if t != f.typ {
+			return fmt.Errorf("soabin: column %s of type %s, want %s", f.name, f.typ, t)
+		}
+		var l [8]byte
+		if _, err := io.ReadFull(&d.r, l[:]); err != nil {
+			return err
+		}
+		if err := c.decode(&d.r, binary.LittleEndian.Uint64(l[:]), d.rows); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (w *countingWriter) Write(p []byte) (int, error) {
+	n, err := w.w.Write(p)
+	w.n += int64(n)
+	return n, err
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,275 @@
+package soabin
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type celsius float64
+
+func TestEncode(t *testing.T) {
+	var b bytes.Buffer
+	n, err := Encode(&b, 2, Col("X", []int16{1, -1}), Col("Name", []string{"a", "bc"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := []byte("SOAB\x01\x00\x00\x00" + // magic and version
+		"\x02\x00\x00\x00\x00\x00\x00\x00" + // rows
+		"\x02\x00\x00\x00" + // columns
+		"\x01\x00\x00\x00X\x05\x00\x00\x00int16" +
+		"\x04\x00\x00\x00Name\x06\x00\x00\x00string" +
+		"\x04\x00\x00\x00\x00\x00\x00\x00\x01\x00\xff\xff" +
+		"\x13\x00\x00\x00\x00\x00\x00\x00" +
+		"\x01\x00\x00\x00\x00\x00\x00\x00\x02\x00\x00\x00\x00\x00\x00\x00abc")
+	if !bytes.Equal(b.Bytes(), want) {
+		t.Errorf("got %q, want %q", b.Bytes(), want)
+	}
+	if n != int64(len(want)) {
+		t.Errorf("got %d bytes, want %d", n, len(want))
+	}
+}
+
+func TestRoundTrip(t *testing.T) {
+	tests := []struct {
+		title string
+		c     any
+	}{
+		{title: "bool", c: []bool{true, false, true}},
+		{title: "int", c: []int{1, -2, 1 << 40}},
+		{title: "int8", c: []int8{1, -2, 127}},
+		{title: "uint", c: []uint{0, 1, 1 << 40}},
+		{title: "uint64", c: []uint64{0, 1, 1 << 63}},
+		{title: "float32", c: []float32{0.5, -1, 3}},
+		{title: "named float64", c: []celsius{36.5, -273.15, 0}},
+		{title: "complex128", c: []complex128{1 + 2i, 0, -1i}},
+		{title: "array", c: [][3]float32{{1, 2, 3}, {}, {4, 5, 6}}},
+		{title: "string", c: []string{"", "foo", "bar baz"}},
+		{title: "bytes", c: [][]byte{nil, []byte("foo"), {0, 1, 2}}},
+		{title: "binary", c: []time.Time{time.Unix(0, 0).UTC(), time.Date(2025, 1, 2, 3, 4, 5, 6, time.UTC), {}}},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			c := reflect.ValueOf(test.c)
+			got := reflect.MakeSlice(c.Type(), c.Len(), c.Len())
+
+			var b bytes.Buffer
+			if _, err := Encode(&b, c.Len(), col(c, "C")); err != nil {
+				t.Fatal(err)
+			}
+			d := NewDecoder(&b)
+			n, err := d.Len()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if n != c.Len() {
+				t.Fatalf("got %d rows, want %d", n, c.Len())
+			}
+			if err := d.Decode(col(got, "C")); err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got.Interface(), test.c) {
+				t.Errorf("got %v, want %v", got, test.c)
+			}
+		})
+	}
+}
+
+// col returns a Column of the slice c.
+func col(c reflect.Value, name string) Column {
+	switch c := c.Interface().(type) {
+	case []bool:
+		return Col(name, c)
+	case []int:
+		return Col(name, c)
+	case []int8:
+		return Col(name, c)
+	case []uint:
+		return Col(name, c)
+	case []uint64:
+		return Col(name, c)
+	case []float32:
+		return Col(name, c)
+	case []celsius:
+		return Col(name, c)
+	case []complex128:
+		return Col(name, c)
+	case [][3]float32:
+		return Col(name, c)
+	case []string:
+		return Col(name, c)
+	case [][]byte:
+		return Col(name, c)
+	case []time.Time:
+		return Col(name, c)
+	default:
+		panic(c)
+	}
+}
+
+func TestDecoder(t *testing.T) {
+	var valid bytes.Buffer
+	if _, err := Encode(&valid, 2, Col("X", []int32{1, 2}), Col("OK", []bool{true, false})); err != nil {
+		t.Fatal(err)
+	}
+	// modify returns a copy of valid with the byte at i replaced.
+	modify := func(i int, b byte) []byte {
+		bs := bytes.Clone(valid.Bytes())
+		bs[i] = b
+		return bs
+	}
+
+	tests := []struct {
+		title string
+		in    []byte
+		cols  func(x []int32, ok []bool) []Column
+		err   error
+		msg   string
+	}{
+		{
+			title: "reordered",
+			in:    valid.Bytes(),
+			cols: func(x []int32, ok []bool) []Column {
+				return []Column{Col("OK", ok), Col("X", x)}
+			},
+		},
+		{title: "magic", in: modify(0, 'X'), err: ErrFormat},
+		{title: "version", in: modify(4, 2), msg: "soabin: unsupported version 2"},
+		{title: "truncated", in: valid.Bytes()[:valid.Len()-1], err: io.ErrUnexpectedEOF},
+		{title: "empty", in: nil, err: io.EOF},
+		{title: "invalid bool", in: modify(valid.Len()-1, 2), msg: "soabin: column OK: invalid bool 2 at byte 1"},
+		{
+			title: "type mismatch",
+			in:    valid.Bytes(),
+			cols: func(x []int32, ok []bool) []Column {
+				return []Column{Col("X", make([]int64, 2)), Col("OK", ok)}
+			},
+			msg: "soabin: column X of type int32, want int64",
+		},
+		{
+			title: "unknown column",
+			in:    valid.Bytes(),
+			cols: func(x []int32, ok []bool) []Column {
+				return []Column{Col("Y", x), Col("OK", ok)}
+			},
+			msg: "soabin: unknown column X",
+		},
+		{
+			title: "missing column",
+			in:    valid.Bytes(),
+			cols: func(x []int32, ok []bool) []Column {
+				return []Column{Col("X", x)}
+			},
+			msg: "soabin: 2 columns, want 1",
+		},
+		{
+			title: "unsupported type",
+			in:    valid.Bytes(),
+			cols: func(x []int32, ok []bool) []Column {
+				return []Column{Col("X", make([]map[string]int, 2)), Col("OK", ok)}
+			},
+			msg: "soabin: unsupported type map[string]int of column X",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			x, ok := make([]int32, 2), make([]bool, 2)
+			cols := []Column{Col("X", x), Col("OK", ok)}
+			if test.cols != nil {
+				cols = test.cols(x, ok)
+			}
+
+			d := NewDecoder(bytes.NewReader(test.in))
+			_, err := d.Len()
+			if err == nil {
+				err = d.Decode(cols...)
+			}
+			switch {
+			case test.err != nil:
+				if !errors.Is(err, test.err) {
+					t.Errorf("got %v, want %v", err, test.err)
+				}
+			case test.msg != "":
+				if err == nil || err.Error() != test.msg {
+					t.Errorf("got %v, want %s", err, test.msg)
+				}
+			case err != nil:
+				t.Fatal(err)
+			default:
+				if !reflect.DeepEqual(x, []int32{1, 2}) || !reflect.DeepEqual(ok, []bool{true, false}) {
+					t.Errorf("got %v and %v", x, ok)
+				}
+				if d.Count() != int64(len(test.in)) {
+					t.Errorf("read %d bytes, want %d", d.Count(), len(test.in))
+				}
+			}
+		})
+	}
+}
+
+func TestInto(t *testing.T) {
+	var valid bytes.Buffer
+	if _, err := Encode(&valid, 2, Col("X", []int32{1, 2}), Col("Name", []string{"a", "bc"})); err != nil {
+		t.Fatal(err)
+	}
+	// rows returns a copy of valid which claims n rows.
+	rows := func(n uint64) []byte {
+		bs := bytes.Clone(valid.Bytes())
+		binary.LittleEndian.PutUint64(bs[8:], n)
+		return bs
+	}
+
+	tests := []struct {
+		title string
+		in    []byte
+		x     []int32
+		name  []string
+		msg   string
+	}{
+		{title: "valid", in: valid.Bytes(), x: []int32{1, 2}, name: []string{"a", "bc"}},
+		{title: "huge rows", in: rows(1 << 50), msg: "soabin: column X: payload of 8 bytes for 1125899906842624 rows"},
+		{title: "fewer rows", in: rows(1), msg: "soabin: column X: payload of 8 bytes for 1 rows"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			var (
+				x    []int32
+				name []string
+			)
+			d := NewDecoder(bytes.NewReader(test.in))
+			if _, err := d.Len(); err != nil {
+				t.Fatal(err)
+			}
+			err := d.Decode(Into("X", &x), Into("Name", &name))
+			if test.msg != "" {
+				if err == nil || err.Error() != test.msg {
+					t.Errorf("got %v, want %s", err, test.msg)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(x, test.x) || !reflect.DeepEqual(name, test.name) {
+				t.Errorf("got %v and %v, want %v and %v", x, name, test.x, test.name)
+			}
+		})
+	}
+}
+
+func TestEncode_Error(t *testing.T) {
+	var b bytes.Buffer
+	if _, err := Encode(&b, 1, Col("F", []func(){nil})); err == nil {
+		t.Error("no error for unsupported type")
+	}
+	if b.Len() != 0 {
+		t.Errorf("wrote %d bytes", b.Len())
+	}
+}
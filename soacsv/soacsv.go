@@ -0,0 +1,310 @@
+// Package soacsv reads and writes SoA slices as CSV with a header. It maps the columns of CSV to the fields of the
+// element type by name with reflection, so it works with any soa.Slice including the ones generated by soagen and
+// soa.Dynamic.
+//
+// The name of a field in the header is the name of the field unless it's tagged with `csv:"name"`. Fields tagged with
+// `csv:"-"` or `soa:"-"` and unexported fields are skipped. Fields of embedded structs are promoted unless the embedded
+// struct is tagged with a name. Fields have to be strings, bools, integers, floating-point numbers, or types which
+// implement both encoding.TextMarshaler and encoding.TextUnmarshaler such as time.Time.
+//
+// Columns of the input which don't match any field are ignored and the fields which don't match any column are left
+// zero. Values which can't be parsed are reported as *csv.ParseError with their lines and columns.
+package soacsv
+
+import (
+	"encoding"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/ichiban/soa"
+)
+
+var (
+	textMarshaler   = reflect.TypeFor[encoding.TextMarshaler]()
+	textUnmarshaler = reflect.TypeFor[encoding.TextUnmarshaler]()
+)
+
+// field is a field of the element type mapped to a column of CSV.
+type field struct {
+	name  string
+	index []int
+}
+
+// fields returns the fields of the struct t in the order of their declarations.
+func fields(t reflect.Type) ([]field, error) {
+	if t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("soacsv: %s is not a struct", t)
+	}
+	var fs []field
+	if err := appendFields(&fs, t, nil); err != nil {
+		return nil, err
+	}
+	seen := make(map[string]bool, len(fs))
+	for _, f := range fs {
+		if seen[f.name] {
+			return nil, fmt.Errorf("soacsv: duplicate column %s", f.name)
+		}
+		seen[f.name] = true
+	}
+	return fs, nil
+}
+
+func appendFields(fs *[]field, t reflect.Type, index []int) error {
+	for i := range t.NumField() {
+		f := t.Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("csv"), ",")
+		if name == "-" || f.Tag.Get("soa") == "-" {
+			continue
+		}
+		idx := append(index[:len(index):len(index)], i)
+		if f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct && !text(f.Type) {
+			if err := appendFields(fs, f.Type, idx); err != nil {
+				return err
+			}
+			continue
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if !supported(f.Type) {
+			return fmt.Errorf("soacsv: unsupported type %s of field %s", f.Type, f.Name)
+		}
+		*fs = append(*fs, field{name: name, index: idx})
+	}
+	return nil
+}
+
+// text reports whether t is encoded as text by encoding.TextMarshaler and encoding.TextUnmarshaler.
+func text(t reflect.Type) bool {
+	return t.Implements(textMarshaler) && reflect.PointerTo(t).Implements(textUnmarshaler)
+}
+
+func supported(t reflect.Type) bool {
+	if text(t) {
+		return true
+	}
+	switch t.Kind() {
+	case reflect.String, reflect.Bool,
+		reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	default:
+		return false
+	}
+}
+
+func format(v reflect.Value) (string, error) {
+	if text(v.Type()) {
+		b, err := v.Interface().(encoding.TextMarshaler).MarshalText()
+		return string(b), err
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'g', -1, v.Type().Bits()), nil
+	default:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	}
+}
+
+func parse(v reflect.Value, s string) error {
+	if text(v.Type()) {
+		return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+	}
+	switch v.Kind() {
+	case reflect.String:
+		v.SetString(s)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return err
+		}
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		x, err := strconv.ParseInt(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetInt(x)
+	case reflect.Float32, reflect.Float64:
+		x, err := strconv.ParseFloat(s, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetFloat(x)
+	default:
+		x, err := strconv.ParseUint(s, 10, v.Type().Bits())
+		if err != nil {
+			return err
+		}
+		v.SetUint(x)
+	}
+	return nil
+}
+
+// Write writes the header and the elements of s to w, and flushes w.
+func Write[S soa.Slice[S, E], E any](w *csv.Writer, s S) error {
+	fs, err := fields(reflect.TypeFor[E]())
+	if err != nil {
+		return err
+	}
+	record := make([]string, len(fs))
+	for i, f := range fs {
+		record[i] = f.name
+	}
+	if err := w.Write(record); err != nil {
+		return err
+	}
+	for i, e := range soa.All(s) {
+		v := reflect.ValueOf(&e).Elem()
+		for j, f := range fs {
+			if record[j], err = format(v.FieldByIndex(f.index)); err != nil {
+				return fmt.Errorf("soacsv: element %d: field %s: %w", i, f.name, err)
+			}
+		}
+		if err := w.Write(record); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
+
+// Read reads all the records of r, the first of which is the header, into a new SoA slice.
+func Read[S soa.Slice[S, E], E any](r *csv.Reader) (S, error) {
+	d := NewDecoder[E](r)
+	var s S
+	s = soa.AppendSeq(s, d.Chunk(-1))
+	return s, d.Err()
+}
+
+// Decoder decodes the records of CSV into elements of E chunk by chunk so that a large input can be processed without
+// reading all of it at once:
+//
+//	d := soacsv.NewDecoder[Point](r)
+//	for d.More() {
+//		s = soa.AppendSeq(s.Slice(0, 0, s.Cap()), d.Chunk(1024))
+//		// process s
+//	}
+//	if err := d.Err(); err != nil {
+//		return err
+//	}
+type Decoder[E any] struct {
+	r *csv.Reader
+	// columns are the fields for the columns of the input. nil if the column doesn't match any field.
+	columns []*field
+	done    bool
+	err     error
+}
+
+// NewDecoder returns a Decoder which reads from r. The first record of r is the header.
+func NewDecoder[E any](r *csv.Reader) *Decoder[E] {
+	return &Decoder[E]{r: r}
+}
+
+// More reports whether there may be more records, i.e. the decoder has reached neither the end of the input nor an
+// error.
+func (d *Decoder[E]) More() bool {
+	return !d.done
+}
+
+// Err returns the error which stopped the decoder or nil if it reached the end of the input.
+func (d *Decoder[E]) Err() error {
+	return d.err
+}
+
+// Chunk returns an iterator over at most n elements decoded from the next records. If n is negative, it iterates over
+// all the rest. It stops early at the end of the input or an error which Err returns.
+func (d *Decoder[E]) Chunk(n int) iter.Seq[E] {
+	return func(yield func(E) bool) {
+		if d.columns == nil && !d.done {
+			d.header()
+		}
+		for i := 0; !d.done && (n < 0 || i < n); i++ {
+			e, ok := d.next()
+			if !ok || !yield(e) {
+				return
+			}
+		}
+	}
+}
+
+func (d *Decoder[E]) header() {
+	fs, err := fields(reflect.TypeFor[E]())
+	if err != nil {
+		d.stop(err)
+		return
+	}
+	record, err := d.r.Read()
+	if err != nil {
+		if err == io.EOF {
+			err = errors.New("soacsv: no header")
+		}
+		d.stop(err)
+		return
+	}
+	byName := make(map[string]*field, len(fs))
+	for i := range fs {
+		byName[fs[i].name] = &fs[i]
+	}
+	d.columns = make([]*field, len(record))
+	for i, name := range record {
+		f, ok := byName[name]
+		if !ok {
+			continue
+		}
+		if f == nil {
+			line, col := d.r.FieldPos(i)
+			d.stop(&csv.ParseError{StartLine: line, Line: line, Column: col, Err: fmt.Errorf("duplicate column %s", name)})
+			return
+		}
+		d.columns[i] = f
+		byName[name] = nil
+	}
+}
+
+// next decodes the next record. It returns false at the end of the input or an error.
+func (d *Decoder[E]) next() (E, bool) {
+	var e E
+	record, err := d.r.Read()
+	if err != nil {
+		if err == io.EOF {
+			err = nil
+		}
+		d.stop(err)
+		return e, false
+	}
+	v := reflect.ValueOf(&e).Elem()
+	for i, s := range record {
+		// A record may be longer than the header if r.FieldsPerRecord is negative.
+		if i >= len(d.columns) || d.columns[i] == nil {
+			continue
+		}
+		f := d.columns[i]
+		if err := parse(v.FieldByIndex(f.index), s); err != nil {
+			line, col := d.r.FieldPos(i)
+			d.stop(&csv.ParseError{StartLine: line, Line: line, Column: col, Err: fmt.Errorf("column %s: %w", f.name, err)})
+			return e, false
+		}
+	}
+	return e, true
+}
+
+func (d *Decoder[E]) stop(err error) {
+	d.done, d.err = true, err
+}
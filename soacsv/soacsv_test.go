@@ -0,0 +1,174 @@
+package soacsv
+
+import (
+	"encoding/csv"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/ichiban/soa"
+)
+
+type Base struct {
+	ID int64 `csv:"id"`
+}
+
+type Measurement struct {
+	Base
+	Name    string
+	Temp    float32 `csv:"temp,omitempty"`
+	OK      bool
+	Count   uint8
+	At      time.Time
+	Comment string `csv:"-"`
+	Cache   []int  `soa:"-"`
+	secret  int
+}
+
+func TestWrite(t *testing.T) {
+	s := soa.FromSlice[soa.Dynamic[Measurement]]([]Measurement{
+		{Base: Base{ID: 1}, Name: "a, b", Temp: 36.6, OK: true, Count: 3, At: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC), Comment: "x"},
+		{Base: Base{ID: -2}, Temp: -1e-7},
+	})
+	var b strings.Builder
+	if err := Write(csv.NewWriter(&b), s); err != nil {
+		t.Fatal(err)
+	}
+	want := "id,Name,temp,OK,Count,At\n" +
+		"1,\"a, b\",36.6,true,3,2025-01-02T03:04:05Z\n" +
+		"-2,,-1e-07,false,0,0001-01-01T00:00:00Z\n"
+	if b.String() != want {
+		t.Errorf("got %q, want %q", b.String(), want)
+	}
+}
+
+func TestRead(t *testing.T) {
+	tests := []struct {
+		title string
+		in    string
+		want  []Measurement
+		err   string
+	}{
+		{
+			title: "reordered and extra columns",
+			in:    "OK,Extra,id,Name\ntrue,foo,1,a\nfalse,bar,2,b\n",
+			want:  []Measurement{{Base: Base{ID: 1}, Name: "a", OK: true}, {Base: Base{ID: 2}, Name: "b"}},
+		},
+		{
+			title: "all types",
+			in:    "id,Name,temp,OK,Count,At\n1,x,0.5,1,255,2025-01-02T03:04:05Z\n",
+			want:  []Measurement{{Base: Base{ID: 1}, Name: "x", Temp: 0.5, OK: true, Count: 255, At: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC)}},
+		},
+		{title: "header only", in: "id,Name\n"},
+		{title: "empty", in: "", err: "soacsv: no header"},
+		{title: "invalid int", in: "id,Name\n1,a\nx,b\n", err: `parse error on line 3, column 1: column id: strconv.ParseInt: parsing "x": invalid syntax`},
+		{title: "overflow", in: "Name,Count\na,256\n", err: `parse error on line 2, column 3: column Count: strconv.ParseUint: parsing "256": value out of range`},
+		{title: "invalid time", in: "At\nyesterday\n", err: `parse error on line 2, column 1: column At: parsing time "yesterday" as "2006-01-02T15:04:05Z07:00": cannot parse "yesterday" as "2006"`},
+		{title: "duplicate column", in: "id,Name,id\n", err: "parse error on line 1, column 9: duplicate column id"},
+		{title: "wrong number of fields", in: "id,Name\n1\n", err: "record on line 2: wrong number of fields"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			s, err := Read[soa.Dynamic[Measurement]](csv.NewReader(strings.NewReader(test.in)))
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Errorf("got %v, want %s", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := soa.ToSlice(s); !reflect.DeepEqual(got, test.want) && len(got)+len(test.want) > 0 {
+				t.Errorf("got %+v, want %+v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestRead_ParseError(t *testing.T) {
+	_, err := Read[soa.Dynamic[Measurement]](csv.NewReader(strings.NewReader("Name,OK\n\"a\nb\",yes\n")))
+	var pe *csv.ParseError
+	if !errors.As(err, &pe) {
+		t.Fatalf("got %v, want *csv.ParseError", err)
+	}
+	if pe.Line != 3 || pe.Column != 4 {
+		t.Errorf("got line %d column %d, want line 3 column 4", pe.Line, pe.Column)
+	}
+}
+
+func TestDecoder_Chunk(t *testing.T) {
+	var in strings.Builder
+	in.WriteString("id\n")
+	for i := range 10 {
+		in.WriteString(strings.Repeat("1", i+1) + "\n")
+	}
+	d := NewDecoder[Measurement](csv.NewReader(strings.NewReader(in.String())))
+	var (
+		s    soa.Dynamic[Measurement]
+		lens []int
+		last int64
+	)
+	for d.More() {
+		s = soa.AppendSeq(s.Slice(0, 0, s.Cap()), d.Chunk(4))
+		lens = append(lens, s.Len())
+		if s.Len() > 0 {
+			last = s.Get(s.Len() - 1).ID
+		}
+	}
+	if err := d.Err(); err != nil {
+		t.Fatal(err)
+	}
+	if want := []int{4, 4, 2}; !reflect.DeepEqual(lens, want) {
+		t.Errorf("got chunks of %v, want %v", lens, want)
+	}
+	if last != 1111111111 {
+		t.Errorf("got last %d", last)
+	}
+}
+
+func TestFields(t *testing.T) {
+	tests := []struct {
+		title string
+		typ   reflect.Type
+		names []string
+		err   string
+	}{
+		{title: "struct", typ: reflect.TypeFor[Measurement](), names: []string{"id", "Name", "temp", "OK", "Count", "At"}},
+		{title: "not a struct", typ: reflect.TypeFor[int](), err: "soacsv: int is not a struct"},
+		{title: "unsupported", typ: reflect.TypeFor[struct{ M map[string]int }](), err: "soacsv: unsupported type map[string]int of field M"},
+		{
+			title: "duplicate",
+			typ: reflect.TypeFor[struct {
+				A int `csv:"x"`
+				B int `csv:"x"`
+			}](),
+			err: "soacsv: duplicate column x",
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			fs, err := fields(test.typ)
+			if test.err != "" {
+				if err == nil || err.Error() != test.err {
+					t.Errorf("got %v, want %s", err, test.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			names := make([]string, len(fs))
+			for i, f := range fs {
+				names[i] = f.name
+			}
+			if !reflect.DeepEqual(names, test.names) {
+				t.Errorf("got %v, want %v", names, test.names)
+			}
+		})
+	}
+}
@@ -0,0 +1,317 @@
+// Package soagen generates SoA slices for structs. It's the library behind the soagen command so that you can embed the
+// generation in your own tools and tests.
+package soagen
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"go/scanner"
+	"go/token"
+	"io"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+
+	"golang.org/x/tools/go/packages"
+
+	"github.com/ichiban/soa/internal/gen"
+)
+
+// Options are the options of Generate.
+type Options struct {
+	// In is the path to the input file.
+	In string
+	// Name is the template of the names of the generated SoA slices. The default is "{{.}}Slice".
+	Name string
+	// Package loads the whole package of the input file with type information.
+	Package bool
+	// Targets are the names of the structs to be processed. If empty, all the structs are processed. A target can be
+	// qualified with an import path, i.e. example.com/models.Order, which implies Package.
+	Targets []string
+
+	// Flatten stores nested struct fields in columns of their fields recursively unless a tag specifies otherwise.
+	Flatten bool
+	// Split stores fixed-size array fields of up to 16 elements in columns of their elements unless a tag
+	// specifies otherwise. Larger arrays are reported as warnings.
+	Split bool
+	// Groups are the fields grouped into columns of structs.
+	Groups []Group
+	// BlockSize is the number of elements stored in a block of each column. If positive, the SoA slices have the
+	// array-of-structures-of-arrays layout and don't have Columns since their columns are slices of blocks.
+	BlockSize int
+	// JSON generates MarshalJSON and UnmarshalJSON which encode the SoA slices in the form if not empty. It's either
+	// "columns", i.e. {"X":[1,2],"Y":[3,4]}, or "rows", i.e. [{"X":1,"Y":3},{"X":2,"Y":4}].
+	JSON string
+	// Binary generates MarshalBinary, UnmarshalBinary, WriteTo, and ReadFrom which encode the SoA slices in the compact
+	// binary columnar format of github.com/ichiban/soa/soabin.
+	Binary bool
+	// Arrow generates WriteArrow and ReadArrow which write and read the SoA slices in the Arrow IPC streaming format with
+	// github.com/ichiban/soa/soaarrow.
+	Arrow bool
+	// SQL generates ScanRows which appends the rows of database/sql to the SoA slices. The columns of the rows are mapped
+	// to the fields by the names in the `db` struct tags or the names of the columns of the SoA slices.
+	SQL bool
+
+	// Template replaces the default template which generates SoA slices if not empty. It's executed with the data
+	// described in the README, i.e. .Structs, and the functions such as join and title.
+	Template string
+	// Extras are the templates executed after Template with the same data. Their outputs are appended to the generated
+	// source. i.e. company-specific methods for every SoA slice.
+	Extras []Extra
+
+	// Test also generates the test of the SoA slices. It asserts they implement soa.Slice, sets and gets random elements
+	// to check every field, and runs soatest.Run.
+	Test bool
+
+	// Output is where the generated source is written if not nil.
+	Output io.Writer
+	// TestOutput is where the generated test is written if not nil and Test is true.
+	TestOutput io.Writer
+}
+
+// Group is a group of fields stored in a column of a struct.
+type Group struct {
+	Name   string
+	Fields []string
+}
+
+// Extra is an extra template.
+type Extra struct {
+	Name string
+	Text string
+}
+
+// Result is the result of Generate.
+type Result struct {
+	// Source is the generated Go source. It's nil if there are any diagnostics of SeverityError.
+	Source []byte
+	// Test is the generated Go test if Options.Test is true. It's nil if Source is nil.
+	Test []byte
+	// Diagnostics are the problems found in the input.
+	Diagnostics []Diagnostic
+}
+
+// Severity is how serious a diagnostic is.
+type Severity int
+
+const (
+	// SeverityError is a problem which prevents the generation.
+	SeverityError Severity = iota
+	// SeverityWarning is something suspicious which doesn't prevent the generation. i.e. a blank field which isn't
+	// stored in the SoA slice
+	SeverityWarning
+)
+
+// Diagnostic is a problem found in the input.
+type Diagnostic struct {
+	// Pos is the position of the problem. It's invalid if the problem isn't specific to a position.
+	Pos      token.Position
+	Severity Severity
+	Message  string
+}
+
+// String returns the diagnostic in the form of file:line:col: message. The message of a warning is prefixed with
+// "warning: ".
+func (d Diagnostic) String() string {
+	m := d.Message
+	if d.Severity == SeverityWarning {
+		m = "warning: " + m
+	}
+	if !d.Pos.IsValid() {
+		return m
+	}
+	return d.Pos.String() + ": " + m
+}
+
+// Errors returns the number of the diagnostics of SeverityError.
+func (r *Result) Errors() int {
+	var n int
+	for _, d := range r.Diagnostics {
+		if d.Severity == SeverityError {
+			n++
+		}
+	}
+	return n
+}
+
+// Generate generates SoA slices for the structs in the input file. Problems in the input are reported as diagnostics of
+// the result while the other failures are returned as an error.
+func Generate(opts Options) (*Result, error) {
+	c := gen.Config{Flatten: opts.Flatten, Split: opts.Split}
+	var (
+		f   gen.File
+		err error
+	)
+	// A target qualified with an import path, i.e. example.com/models.Order, requires type information.
+	if opts.Package || slices.ContainsFunc(opts.Targets, func(t string) bool {
+		return strings.Contains(t, ".")
+	}) {
+		f, err = gen.LoadPackage(filepath.Dir(opts.In), c, opts.Targets...)
+	} else {
+		f, err = gen.ParseFile(opts.In, c, opts.Targets...)
+	}
+	ws := make([]Diagnostic, len(f.Warnings))
+	for i, w := range f.Warnings {
+		ws[i] = Diagnostic{Pos: w.Pos, Severity: SeverityWarning, Message: w.Err.Error()}
+	}
+	if err != nil {
+		ds := diagnostics(err)
+		if ds == nil {
+			return nil, err
+		}
+		return &Result{Diagnostics: append(ds, ws...)}, nil
+	}
+
+	t, err := gen.NewTemplate(opts.Template)
+	if err != nil {
+		return nil, err
+	}
+	for _, e := range opts.Extras {
+		if err := t.AddExtra(e.Name, e.Text); err != nil {
+			return nil, err
+		}
+	}
+
+	name := opts.Name
+	if name == "" {
+		name = "{{.}}Slice"
+	}
+	n, err := template.New("").Parse(name)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		ds = ws
+		sb strings.Builder
+	)
+	for i := range f.Structs {
+		s := &f.Structs[i]
+
+		sb.Reset()
+		if err := n.Execute(&sb, s.Name); err != nil {
+			return nil, err
+		}
+
+		if err := s.SetSliceName(sb.String()); err != nil {
+			ds = append(ds, Diagnostic{Message: fmt.Sprintf("%s: %v", s.Name, err)})
+		}
+
+		for _, g := range opts.Groups {
+			if err := s.Group(g.Name, g.Fields...); err != nil {
+				ds = append(ds, Diagnostic{Message: fmt.Sprintf("%s: %v", s.Name, err)})
+			}
+		}
+
+		if err := s.SetBlockSize(opts.BlockSize); err != nil {
+			ds = append(ds, Diagnostic{Message: fmt.Sprintf("%s: %v", s.Name, err)})
+		}
+
+		if err := s.SetJSON(opts.JSON); err != nil {
+			ds = append(ds, Diagnostic{Message: fmt.Sprintf("%s: %v", s.Name, err)})
+		}
+		if err := s.SetBinary(opts.Binary); err != nil {
+			ds = append(ds, Diagnostic{Message: fmt.Sprintf("%s: %v", s.Name, err)})
+		}
+		if err := s.SetArrow(opts.Arrow); err != nil {
+			ds = append(ds, Diagnostic{Message: fmt.Sprintf("%s: %v", s.Name, err)})
+		}
+		if err := s.SetSQL(opts.SQL); err != nil {
+			ds = append(ds, Diagnostic{Message: fmt.Sprintf("%s: %v", s.Name, err)})
+		}
+	}
+	if r := (Result{Diagnostics: ds}); r.Errors() > 0 {
+		return &r, nil
+	}
+
+	var buf bytes.Buffer
+	if _, err := t.Execute(&buf, &f); err != nil {
+		return nil, err
+	}
+	r := Result{Source: buf.Bytes(), Diagnostics: ds}
+	if opts.Test {
+		var buf bytes.Buffer
+		if _, err := f.WriteTestTo(&buf); err != nil {
+			return nil, err
+		}
+		r.Test = buf.Bytes()
+	}
+	if opts.Output != nil {
+		if _, err := opts.Output.Write(r.Source); err != nil {
+			return nil, err
+		}
+	}
+	if opts.TestOutput != nil && r.Test != nil {
+		if _, err := opts.TestOutput.Write(r.Test); err != nil {
+			return nil, err
+		}
+	}
+	return &r, nil
+}
+
+// diagnostics returns the diagnostics for the problems in the input reported by err. It returns nil if any of the
+// joined errors isn't a problem in the input.
+func diagnostics(err error) []Diagnostic {
+	if j, ok := err.(interface{ Unwrap() []error }); ok {
+		var ds []Diagnostic
+		for _, e := range j.Unwrap() {
+			d := diagnostics(e)
+			if d == nil {
+				return nil
+			}
+			ds = append(ds, d...)
+		}
+		return ds
+	}
+
+	var (
+		ge *gen.Error
+		el scanner.ErrorList
+		pe packages.Error
+	)
+	switch {
+	case errors.As(err, &ge):
+		return []Diagnostic{{Pos: ge.Pos, Message: ge.Err.Error()}}
+	case errors.As(err, &el):
+		ds := make([]Diagnostic, len(el))
+		for i, e := range el {
+			ds[i] = Diagnostic{Pos: e.Pos, Message: e.Msg}
+		}
+		return ds
+	case errors.As(err, &pe):
+		return []Diagnostic{{Pos: position(pe.Pos), Message: pe.Msg}}
+	default:
+		return nil
+	}
+}
+
+// position parses a position in the form of file:line:col or file:line.
+func position(s string) token.Position {
+	var p token.Position
+	rest, last, ok := cut(s)
+	if !ok {
+		return p
+	}
+	if file, line, ok := cut(rest); ok {
+		p.Filename, p.Line, p.Column = file, line, last
+		return p
+	}
+	p.Filename, p.Line = rest, last
+	return p
+}
+
+// cut cuts a trailing :n off s.
+func cut(s string) (string, int, bool) {
+	i := strings.LastIndex(s, ":")
+	if i < 0 {
+		return s, 0, false
+	}
+	n, err := strconv.Atoi(s[i+1:])
+	if err != nil {
+		return s, 0, false
+	}
+	return s[:i], n, true
+}
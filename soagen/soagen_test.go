@@ -0,0 +1,231 @@
+package soagen
+
+import (
+	"bytes"
+	"go/token"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		title       string
+		opts        Options
+		slices      []string
+		tests       []string
+		diagnostics []string
+		err         bool
+	}{
+		{
+			title:  "default name",
+			opts:   Options{In: "testdata/point.go", Targets: []string{"Point"}},
+			slices: []string{"PointSlice"},
+		},
+		{
+			title:  "name",
+			opts:   Options{In: "testdata/point.go", Name: "{{.}}s", Targets: []string{"Point"}},
+			slices: []string{"Points"},
+		},
+		{
+			title:  "block",
+			opts:   Options{In: "testdata/point.go", Targets: []string{"Point"}, BlockSize: 4},
+			slices: []string{"PointSlice"},
+		},
+		{
+			title:       "tag",
+			opts:        Options{In: "testdata/point.go"},
+			diagnostics: []string{`testdata/point.go:8:2: unknown option "unknown"`},
+		},
+		{
+			title:       "group",
+			opts:        Options{In: "testdata/point.go", Targets: []string{"Point"}, Groups: []Group{{Name: "X", Fields: []string{"Y"}}}},
+			diagnostics: []string{"Point: duplicate column X"},
+		},
+		{
+			title:       "group of unknown field",
+			opts:        Options{In: "testdata/point.go", Targets: []string{"Point"}, Groups: []Group{{Name: "Hot", Fields: []string{"X", "Z"}}}},
+			diagnostics: []string{"Point: unknown field Z"},
+		},
+		{
+			title:       "group of Ref",
+			opts:        Options{In: "testdata/point.go", Targets: []string{"Point"}, Groups: []Group{{Name: "ref", Fields: []string{"X", "Y"}}}},
+			diagnostics: []string{"Point: group ref conflicts with type PointSliceRef"},
+		},
+		{
+			title:       "binary of slice",
+			opts:        Options{In: "testdata/point.go", Targets: []string{"Polygon"}, Binary: true},
+			diagnostics: []string{"Polygon: unsupported type []Point of column Points in the binary format"},
+		},
+		{
+			title:       "arrow of slice",
+			opts:        Options{In: "testdata/point.go", Targets: []string{"Polygon"}, Arrow: true},
+			diagnostics: []string{"Polygon: unsupported type []Point of column Points in the Arrow format"},
+		},
+		{
+			title:       "syntax error",
+			opts:        Options{In: "testdata/syntax.go.txt"},
+			diagnostics: []string{"testdata/syntax.go.txt:4:8: expected '}', found 'EOF'"},
+		},
+		{
+			title:       "warning",
+			opts:        Options{In: "testdata/suspicious.go", Targets: []string{"Blank"}},
+			slices:      []string{"BlankSlice"},
+			diagnostics: []string{"testdata/suspicious.go:5:2: warning: blank field is not stored"},
+		},
+		{
+			title: "multiple problems",
+			opts:  Options{In: "testdata/suspicious.go", Targets: []string{"Blank", "Celsius", "Missing"}},
+			diagnostics: []string{
+				"testdata/suspicious.go:8:6: type Celsius is not a struct",
+				"type Missing not found",
+				"testdata/suspicious.go:5:2: warning: blank field is not stored",
+			},
+		},
+		{
+			title:  "json",
+			opts:   Options{In: "testdata/point.go", Targets: []string{"Point"}, JSON: "rows"},
+			slices: []string{"PointSlice"},
+		},
+		{
+			title:  "binary",
+			opts:   Options{In: "testdata/point.go", Targets: []string{"Point"}, Binary: true},
+			slices: []string{"PointSlice"},
+		},
+		{
+			title:  "sql",
+			opts:   Options{In: "testdata/point.go", Targets: []string{"Point"}, SQL: true},
+			slices: []string{"PointSlice"},
+		},
+		{
+			title:       "duplicate db column",
+			opts:        Options{In: "testdata/point.go", Targets: []string{"Row"}, SQL: true},
+			diagnostics: []string{"Row: duplicate db column id"},
+		},
+		{
+			title:  "arrow",
+			opts:   Options{In: "testdata/point.go", Targets: []string{"Point"}, Arrow: true},
+			slices: []string{"PointSlice"},
+		},
+		{
+			title:       "unknown json form",
+			opts:        Options{In: "testdata/point.go", Targets: []string{"Point"}, JSON: "tables"},
+			diagnostics: []string{`Point: unknown JSON form "tables"`},
+		},
+		{
+			title:  "test",
+			opts:   Options{In: "testdata/point.go", Targets: []string{"Point"}, Test: true},
+			slices: []string{"PointSlice"},
+			tests:  []string{"TestPointSlice"},
+		},
+		{
+			title:  "template",
+			opts:   Options{In: "testdata/point.go", Targets: []string{"Point"}, Template: "package {{.PackageName}}\n{{range .Structs}}type {{.SliceName}} struct{}{{end}}"},
+			slices: []string{"PointSlice"},
+		},
+		{
+			title: "extras",
+			opts: Options{In: "testdata/point.go", Targets: []string{"Point"}, Extras: []Extra{
+				{Name: "validate", Text: "{{range .Structs}}type {{.SliceName}}Validator struct{}{{end}}"},
+			}},
+			slices: []string{"PointSlice", "PointSliceValidator"},
+		},
+		{
+			title: "invalid template",
+			opts:  Options{In: "testdata/point.go", Targets: []string{"Point"}, Template: "{{"},
+			err:   true,
+		},
+		{
+			title: "invalid extra",
+			opts:  Options{In: "testdata/point.go", Targets: []string{"Point"}, Extras: []Extra{{Name: "x", Text: "{{"}}},
+			err:   true,
+		},
+		{
+			title: "invalid name",
+			opts:  Options{In: "testdata/point.go", Name: "{{", Targets: []string{"Point"}},
+			err:   true,
+		},
+		{
+			title: "no file",
+			opts:  Options{In: "testdata/missing.go"},
+			err:   true,
+		},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			var buf, tbuf bytes.Buffer
+			test.opts.Output = &buf
+			test.opts.TestOutput = &tbuf
+			r, err := Generate(test.opts)
+			if (err != nil) != test.err {
+				t.Fatalf("error: %v", err)
+			}
+			if err != nil {
+				return
+			}
+			var ds []string
+			for _, d := range r.Diagnostics {
+				ds = append(ds, d.String())
+			}
+			if !reflect.DeepEqual(ds, test.diagnostics) {
+				t.Errorf("got %v, want %v", ds, test.diagnostics)
+			}
+			if !bytes.Equal(buf.Bytes(), r.Source) {
+				t.Errorf("output %q doesn't match source %q", buf.Bytes(), r.Source)
+			}
+			for _, s := range test.slices {
+				if !strings.Contains(string(r.Source), "type "+s+" struct") {
+					t.Errorf("%s not found in %s", s, r.Source)
+				}
+			}
+			if !bytes.Equal(tbuf.Bytes(), r.Test) {
+				t.Errorf("test output %q doesn't match test %q", tbuf.Bytes(), r.Test)
+			}
+			for _, s := range test.tests {
+				if !strings.Contains(string(r.Test), "func "+s+"(t *testing.T)") {
+					t.Errorf("%s not found in %s", s, r.Test)
+				}
+			}
+		})
+	}
+}
+
+func TestDiagnostic_String(t *testing.T) {
+	tests := []struct {
+		title string
+		d     Diagnostic
+		s     string
+	}{
+		{title: "position", d: Diagnostic{Pos: token.Position{Filename: "a.go", Line: 1, Column: 2}, Message: "oops"}, s: "a.go:1:2: oops"},
+		{title: "no position", d: Diagnostic{Message: "oops"}, s: "oops"},
+		{title: "warning", d: Diagnostic{Pos: token.Position{Filename: "a.go", Line: 1, Column: 2}, Severity: SeverityWarning, Message: "hmm"}, s: "a.go:1:2: warning: hmm"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.title, func(t *testing.T) {
+			if got := test.d.String(); got != test.s {
+				t.Errorf("got %v, want %v", got, test.s)
+			}
+		})
+	}
+}
+
+func TestPosition(t *testing.T) {
+	tests := []struct {
+		s   string
+		pos token.Position
+	}{
+		{s: "a.go:1:2", pos: token.Position{Filename: "a.go", Line: 1, Column: 2}},
+		{s: "a.go:1", pos: token.Position{Filename: "a.go", Line: 1}},
+		{s: "-"},
+	}
+
+	for _, test := range tests {
+		t.Run(test.s, func(t *testing.T) {
+			if got := position(test.s); got != test.pos {
+				t.Errorf("got %v, want %v", got, test.pos)
+			}
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package testdata
+
+type Point struct {
+	X, Y int
+}
+
+type Tagged struct {
+	X int `soa:",unknown"`
+}
+
+type Row struct {
+	ID     int `db:"id"`
+	UserID int `db:"id"`
+}
+
+type Polygon struct {
+	Name   string
+	Points []Point
+}
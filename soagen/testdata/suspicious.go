@@ -0,0 +1,8 @@
+package testdata
+
+type Blank struct {
+	X int
+	_ int
+}
+
+type Celsius float64
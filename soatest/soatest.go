@@ -0,0 +1,319 @@
+// Package soatest tests if an implementation of soa.Slice is a drop-in replacement for a slice of its element type.
+package soatest
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/ichiban/soa"
+)
+
+// Run tests S against []E with the functions of soa and the methods of soa.Slice. gen returns an element every time
+// it's called. The elements are compared with reflect.DeepEqual and ordered by the order gen returned them.
+func Run[S soa.Slice[S, E], E any](t *testing.T, gen func() E) {
+	t.Helper()
+
+	p := newTester[S](gen, 8)
+
+	t.Run("Make", func(t *testing.T) {
+		for _, c := range [][2]int{{0, 0}, {0, 5}, {3, 3}, {3, 10}} {
+			s := soa.Make[S](c[0], c[1])
+			if s.Len() != c[0] || s.Cap() != c[1] {
+				t.Errorf("Make(%d, %d): len %d, cap %d", c[0], c[1], s.Len(), s.Cap())
+			}
+		}
+	})
+
+	t.Run("Get/Set", func(t *testing.T) {
+		want := p.values(20)
+		s := soa.Make[S](len(want), len(want))
+		for i, e := range want {
+			s.Set(i, e)
+		}
+		check(t, "Set", s, want)
+		for _, i := range []int{-1, len(want)} {
+			panics(t, fmt.Sprintf("Get(%d)", i), func() { s.Get(i) })
+			panics(t, fmt.Sprintf("Set(%d)", i), func() { s.Set(i, want[0]) })
+		}
+	})
+
+	t.Run("Slice", func(t *testing.T) {
+		want := p.values(10)
+		s := soa.FromSlice[S](want)
+		s = s.Grow(6).Slice(0, 10, 16)
+		for _, b := range [][3]int{{0, 0, 0}, {0, 10, 10}, {2, 5, 7}, {5, 10, 16}, {10, 12, 16}, {16, 16, 16}} {
+			sub := s.Slice(b[0], b[1], b[2])
+			if sub.Len() != b[1]-b[0] || sub.Cap() != b[2]-b[0] {
+				t.Errorf("Slice(%d, %d, %d): len %d, cap %d", b[0], b[1], b[2], sub.Len(), sub.Cap())
+			}
+			if b[1] <= len(want) {
+				check(t, fmt.Sprintf("Slice(%d, %d, %d)", b[0], b[1], b[2]), sub, want[b[0]:b[1]])
+			}
+		}
+		for _, b := range [][3]int{{-1, 0, 0}, {2, 1, 3}, {0, 3, 2}, {0, 0, 17}} {
+			panics(t, fmt.Sprintf("Slice(%d, %d, %d)", b[0], b[1], b[2]), func() { s.Slice(b[0], b[1], b[2]) })
+		}
+
+		// A slice shares the elements with the original.
+		sub := s.Slice(3, 6, 8)
+		sub.Set(1, want[0])
+		if e := s.Get(4); !reflect.DeepEqual(e, want[0]) {
+			t.Errorf("Set on a slice didn't change the original: %v", e)
+		}
+		// Elements beyond the length can be reached by reslicing.
+		sub = sub.Slice(0, 5, 5)
+		check(t, "reslice", sub, append(append(append([]E{}, want[3:4]...), want[0]), want[5:8]...))
+	})
+
+	t.Run("Grow", func(t *testing.T) {
+		want := p.values(5)
+		s := soa.FromSlice[S](want)
+		for _, n := range []int{0, 1, 10, 100} {
+			g := s.Grow(n)
+			if g.Cap() < g.Len()+n {
+				t.Errorf("Grow(%d): len %d, cap %d", n, g.Len(), g.Cap())
+			}
+			check(t, fmt.Sprintf("Grow(%d)", n), g, want)
+		}
+		panics(t, "Grow(-1)", func() { s.Grow(-1) })
+
+		// Appending to a clipped slice doesn't change the original.
+		c := soa.Clip(s.Slice(0, 2, 5))
+		if c.Cap() != 2 {
+			t.Errorf("Clip: cap %d", c.Cap())
+		}
+		_ = soa.Append(c, want[4])
+		check(t, "Append to a clipped slice", s, want)
+
+		// Appending within the capacity overwrites the original.
+		_ = soa.Append(s.Slice(0, 2, 5), want[4])
+		check(t, "Append within the capacity", s, []E{want[0], want[1], want[4], want[3], want[4]})
+	})
+
+	t.Run("functions", func(t *testing.T) {
+		var (
+			s   S
+			ref []E
+		)
+		for i := 0; i < 500; i++ {
+			s, ref = p.mutate(t, s, ref)
+			check(t, fmt.Sprintf("step %d", i), s, ref)
+			if t.Failed() {
+				return
+			}
+			p.read(t, s, ref)
+		}
+	})
+}
+
+// tester has elements ordered by the order they were generated.
+type tester[S soa.Slice[S, E], E any] struct {
+	pool []E
+	r    *rand.Rand
+}
+
+func newTester[S soa.Slice[S, E], E any](gen func() E, n int) *tester[S, E] {
+	p := tester[S, E]{
+		pool: make([]E, n),
+		r:    rand.New(rand.NewSource(1)),
+	}
+	for i := range p.pool {
+		p.pool[i] = gen()
+	}
+	return &p
+}
+
+// values returns n elements chosen from the pool at random.
+func (p *tester[S, E]) values(n int) []E {
+	es := make([]E, n)
+	for i := range es {
+		es[i] = p.pool[p.r.Intn(len(p.pool))]
+	}
+	return es
+}
+
+// rank returns the index of the first element in the pool equal to e.
+func (p *tester[S, E]) rank(e E) int {
+	for i, x := range p.pool {
+		if equal(x, e) {
+			return i
+		}
+	}
+	return len(p.pool)
+}
+
+func (p *tester[S, E]) cmp(a, b E) int {
+	return p.rank(a) - p.rank(b)
+}
+
+// mutate applies a random mutation to both s and ref.
+func (p *tester[S, E]) mutate(t *testing.T, s S, ref []E) (S, []E) {
+	t.Helper()
+
+	l := len(ref)
+	i := p.r.Intn(l + 1)
+	j := i + p.r.Intn(l-i+1)
+	v := p.values(p.r.Intn(4))
+	switch p.r.Intn(16) {
+	case 0:
+		return soa.Append(s, v...), append(ref, v...)
+	case 1:
+		return soa.AppendSeq(s, slices.Values(v)), append(ref, v...)
+	case 2:
+		return soa.Insert(s, i, v...), slices.Insert(ref, i, v...)
+	case 3:
+		return soa.Delete(s, i, j), slices.Delete(ref, i, j)
+	case 4:
+		return soa.Replace(s, i, j, v...), slices.Replace(ref, i, j, v...)
+	case 5:
+		soa.Reverse(s)
+		slices.Reverse(ref)
+	case 6:
+		soa.SortFunc(s, p.cmp)
+		slices.SortFunc(ref, p.cmp)
+	case 7:
+		soa.SortStableFunc(s, p.cmp)
+		slices.SortStableFunc(ref, p.cmp)
+	case 8:
+		even := func(e E) bool { return p.rank(e)%2 == 0 }
+		return soa.DeleteFunc(s, even), slices.DeleteFunc(ref, even)
+	case 9:
+		return soa.CompactFunc(s, equal[E]), slices.CompactFunc(ref, equal[E])
+	case 10:
+		return soa.Clip(s), slices.Clip(ref)
+	case 11:
+		n := p.r.Intn(10)
+		return s.Grow(n), slices.Grow(ref, n)
+	case 12:
+		return s.Slice(i, j, s.Cap()), ref[i:j]
+	case 13:
+		if i < l && len(v) > 0 {
+			s.Set(i, v[0])
+			ref[i] = v[0]
+		}
+	case 14:
+		if n := soa.Copy(s.Slice(i, l, s.Cap()), soa.FromSlice[S](v)); n != copy(ref[i:], v) {
+			t.Errorf("Copy: %d", n)
+		}
+	case 15:
+		soa.Clear(s.Slice(i, j, s.Cap()))
+		clear(ref[i:j])
+	}
+	return s, ref
+}
+
+// read checks the functions which don't modify s.
+func (p *tester[S, E]) read(t *testing.T, s S, ref []E) {
+	t.Helper()
+
+	if s.Cap() < s.Len() {
+		t.Errorf("cap %d < len %d", s.Cap(), s.Len())
+	}
+
+	if got := soa.ToSlice(s); !slices.EqualFunc(got, ref, equal[E]) {
+		t.Errorf("ToSlice: %v != %v", got, ref)
+	}
+	if got := slices.Collect(soa.Values(s)); !slices.EqualFunc(got, ref, equal[E]) {
+		t.Errorf("Values: %v != %v", got, ref)
+	}
+	for i, e := range soa.All(s) {
+		if !reflect.DeepEqual(e, ref[i]) {
+			t.Errorf("All: %v != %v at %d", e, ref[i], i)
+		}
+	}
+	for i, e := range soa.Backward(s) {
+		if !reflect.DeepEqual(e, ref[i]) {
+			t.Errorf("Backward: %v != %v at %d", e, ref[i], i)
+		}
+	}
+
+	c := soa.Clone(s)
+	if !soa.EqualFunc(c, s, equal[E]) {
+		t.Errorf("Clone: %v != %v", soa.ToSlice(c), ref)
+	}
+	if len(ref) > 0 {
+		c.Set(0, p.pool[(p.rank(ref[0])+1)%len(p.pool)])
+		if !reflect.DeepEqual(s.Get(0), ref[0]) {
+			t.Error("Clone shares the elements with the original")
+		}
+	}
+
+	if got, want := soa.CompareFunc(s, soa.FromSlice[S](p.pool), p.cmp), slices.CompareFunc(ref, p.pool, p.cmp); got != want {
+		t.Errorf("CompareFunc: %d != %d", got, want)
+	}
+	for _, e := range p.pool {
+		is := func(x E) bool { return equal(x, e) }
+		if got, want := soa.IndexFunc(s, is), slices.IndexFunc(ref, is); got != want {
+			t.Errorf("IndexFunc: %d != %d", got, want)
+		}
+		if got, want := soa.ContainsFunc(s, is), slices.ContainsFunc(ref, is); got != want {
+			t.Errorf("ContainsFunc: %t != %t", got, want)
+		}
+	}
+
+	sorted := slices.IsSortedFunc(ref, p.cmp)
+	if got := soa.IsSortedFunc(s, p.cmp); got != sorted {
+		t.Errorf("IsSortedFunc: %t != %t", got, sorted)
+	}
+	if sorted {
+		for _, e := range p.pool {
+			i, ok := soa.BinarySearchFunc(s, e, p.cmp)
+			j, found := slices.BinarySearchFunc(ref, e, p.cmp)
+			if i != j || ok != found {
+				t.Errorf("BinarySearchFunc: (%d, %t) != (%d, %t)", i, ok, j, found)
+			}
+		}
+	}
+	if len(ref) > 0 {
+		if got, want := soa.MaxFunc(s, p.cmp), slices.MaxFunc(ref, p.cmp); !equal(got, want) {
+			t.Errorf("MaxFunc: %v != %v", got, want)
+		}
+		if got, want := soa.MinFunc(s, p.cmp), slices.MinFunc(ref, p.cmp); !equal(got, want) {
+			t.Errorf("MinFunc: %v != %v", got, want)
+		}
+	}
+
+	var chunks [][]E
+	for c := range soa.Chunk(s, 3) {
+		chunks = append(chunks, soa.ToSlice(c))
+	}
+	if want := slices.Collect(slices.Chunk(slices.Clone(ref), 3)); !reflect.DeepEqual(chunks, want) {
+		t.Errorf("Chunk: %v != %v", chunks, want)
+	}
+
+	check(t, "Concat", soa.Concat(s, s), slices.Concat(ref, ref))
+	check(t, "Repeat", soa.Repeat(s, 2), slices.Repeat(ref, 2))
+	check(t, "SortedFunc", soa.SortedFunc[S](soa.Values(s), p.cmp), slices.SortedFunc(slices.Values(ref), p.cmp))
+	check(t, "SortedStableFunc", soa.SortedStableFunc[S](soa.Values(s), p.cmp), slices.SortedStableFunc(slices.Values(ref), p.cmp))
+}
+
+// check checks if s has the same elements as want.
+func check[S soa.Slice[S, E], E any](t *testing.T, name string, s S, want []E) {
+	t.Helper()
+	if s.Len() != len(want) {
+		t.Errorf("%s: len %d != %d", name, s.Len(), len(want))
+		return
+	}
+	for i, e := range want {
+		if got := s.Get(i); !reflect.DeepEqual(got, e) {
+			t.Errorf("%s: %v != %v at %d", name, got, e, i)
+		}
+	}
+}
+
+func panics(t *testing.T, name string, f func()) {
+	t.Helper()
+	defer func() {
+		if recover() == nil {
+			t.Errorf("%s didn't panic", name)
+		}
+	}()
+	f()
+}
+
+func equal[E any](a, b E) bool {
+	return reflect.DeepEqual(a, b)
+}
@@ -0,0 +1,120 @@
+package soatest_test
+
+import (
+	"math"
+	"math/rand"
+	"reflect"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/ichiban/soa"
+	"github.com/ichiban/soa/soatest"
+)
+
+type Point struct {
+	X, Y int
+	Tags []string
+}
+
+type PointSlice struct {
+	X, Y []int
+	Tags [][]string
+}
+
+func (s PointSlice) Get(i int) Point {
+	return Point{X: s.X[i], Y: s.Y[i], Tags: s.Tags[i]}
+}
+
+func (s PointSlice) Set(i int, p Point) {
+	s.X[i], s.Y[i], s.Tags[i] = p.X, p.Y, p.Tags
+}
+
+func (s PointSlice) Len() int {
+	return min(len(s.X), len(s.Y), len(s.Tags))
+}
+
+func (s PointSlice) Cap() int {
+	return min(cap(s.X), cap(s.Y), cap(s.Tags))
+}
+
+func (s PointSlice) Slice(low, high, max int) PointSlice {
+	return PointSlice{X: s.X[low:high:max], Y: s.Y[low:high:max], Tags: s.Tags[low:high:max]}
+}
+
+func (s PointSlice) Grow(n int) PointSlice {
+	return PointSlice{X: slices.Grow(s.X, n), Y: slices.Grow(s.Y, n), Tags: slices.Grow(s.Tags, n)}
+}
+
+func (s PointSlice) Swap(i, j int) {
+	s.X[i], s.X[j] = s.X[j], s.X[i]
+	s.Y[i], s.Y[j] = s.Y[j], s.Y[i]
+	s.Tags[i], s.Tags[j] = s.Tags[j], s.Tags[i]
+}
+
+func (s PointSlice) CopyWithin(dst, src, n int) {
+	copy(s.X[dst:dst+n], s.X[src:src+n])
+	copy(s.Y[dst:dst+n], s.Y[src:src+n])
+	copy(s.Tags[dst:dst+n], s.Tags[src:src+n])
+}
+
+func point() Point {
+	p := Point{X: rand.Intn(100), Y: rand.Intn(100)}
+	if rand.Intn(2) == 0 {
+		p.Tags = []string{"a"}
+	}
+	return p
+}
+
+func TestRun(t *testing.T) {
+	t.Run("PointSlice", func(t *testing.T) {
+		soatest.Run[PointSlice](t, point)
+	})
+	t.Run("Dynamic", func(t *testing.T) {
+		soatest.Run[soa.Dynamic[Point]](t, point)
+	})
+}
+
+type Record struct {
+	ID      int
+	Name    string
+	Score   float64
+	At      time.Time
+	Next    *Record
+	Labels  map[string][]byte
+	deleted bool
+	cache   []int `soa:"-"`
+	_       int
+}
+
+func TestValue(t *testing.T) {
+	r := rand.New(rand.NewSource(1))
+	var deleted, next int
+	for range 100 {
+		e := soatest.Value[Record](r)
+		if math.IsNaN(e.Score) {
+			t.Error("NaN")
+		}
+		if !e.At.IsZero() {
+			t.Errorf("unexported fields of another package are filled: %v", e.At)
+		}
+		if e.cache != nil {
+			t.Errorf("a field tagged with soa:\"-\" is filled: %v", e.cache)
+		}
+		if e.deleted {
+			deleted++
+		}
+		if e.Next != nil {
+			next++
+		}
+		if !reflect.DeepEqual(e, e) {
+			t.Errorf("not equal to itself: %v", e)
+		}
+	}
+	if deleted == 0 {
+		t.Error("unexported fields of the package are not filled")
+	}
+	if next == 0 {
+		t.Error("pointers are always nil")
+	}
+}
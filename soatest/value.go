@@ -0,0 +1,88 @@
+package soatest
+
+import (
+	"math/rand"
+	"reflect"
+	"unsafe"
+)
+
+// depth is how deep Value follows pointers, slices, and maps so that recursive types terminate.
+const depth = 3
+
+// Value returns a random value of E. Fields of E and its nested structs declared in the package of E are filled even if
+// they're unexported while unexported fields of the other packages are left zero, i.e. the internals of time.Time.
+// Fields tagged with `soa:"-"` are left zero as well since SoA slices don't store them. Interfaces, channels, and
+// functions are left nil. Floating-point numbers are never NaN so that the values can be compared with reflect.DeepEqual.
+func Value[E any](r *rand.Rand) E {
+	var e E
+	v := reflect.ValueOf(&e).Elem()
+	fill(r, v, reflect.TypeFor[E]().PkgPath(), depth)
+	return e
+}
+
+// fill sets a random value to v. pkg is the path of the package whose unexported fields are filled.
+func fill(r *rand.Rand, v reflect.Value, pkg string, d int) {
+	switch v.Kind() {
+	case reflect.Bool:
+		v.SetBool(r.Intn(2) == 0)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(r.Uint64()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		v.SetUint(r.Uint64())
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(r.NormFloat64())
+	case reflect.Complex64, reflect.Complex128:
+		v.SetComplex(complex(r.NormFloat64(), r.NormFloat64()))
+	case reflect.String:
+		b := make([]byte, r.Intn(8))
+		for i := range b {
+			b[i] = byte('a' + r.Intn(26))
+		}
+		v.SetString(string(b))
+	case reflect.Array:
+		for i := range v.Len() {
+			fill(r, v.Index(i), pkg, d)
+		}
+	case reflect.Slice:
+		if d == 0 {
+			return
+		}
+		n := r.Intn(4)
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		for i := range n {
+			fill(r, v.Index(i), pkg, d-1)
+		}
+	case reflect.Map:
+		if d == 0 {
+			return
+		}
+		v.Set(reflect.MakeMap(v.Type()))
+		for range r.Intn(4) {
+			k, e := reflect.New(v.Type().Key()).Elem(), reflect.New(v.Type().Elem()).Elem()
+			fill(r, k, pkg, d-1)
+			fill(r, e, pkg, d-1)
+			v.SetMapIndex(k, e)
+		}
+	case reflect.Pointer:
+		// A nil pointer is a valid value as well.
+		if d == 0 || r.Intn(4) == 0 {
+			return
+		}
+		p := reflect.New(v.Type().Elem())
+		fill(r, p.Elem(), pkg, d-1)
+		v.Set(p)
+	case reflect.Struct:
+		t := v.Type()
+		for i := range t.NumField() {
+			f := t.Field(i)
+			if f.Name == "_" || f.Tag.Get("soa") == "-" || !f.IsExported() && f.PkgPath != pkg {
+				continue
+			}
+			fv := v.Field(i)
+			if !f.IsExported() {
+				fv = reflect.NewAt(f.Type, unsafe.Pointer(fv.UnsafeAddr())).Elem()
+			}
+			fill(r, fv, pkg, d)
+		}
+	}
+}